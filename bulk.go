@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// A countingWriter counts the bytes and lines written through it.
+type countingWriter struct {
+	w      io.Writer
+	n      int64
+	nlines int
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.nlines += bytes.Count(b[:n], []byte{'\n'})
+	return n, err
+}
+
+// progressInterval is how many Things bulk marshals between updates of
+// its progress line.
+const progressInterval = 1000
+
+// bulk marshals a stream of Things chosen at random from the registered
+// names and reports throughput on stderr.  The choice is driven by -seed,
+// so a given seed always produces the same stream.  Each Thing is made
+// just before it is marshaled, so memory use does not grow with -count.
+// The stream itself is discarded unless -o was given.  An interrupt stops
+// the run cleanly, as does the deadline set by -timeout.  When stderr is
+// a terminal but stdout is not, a progress line on stderr is updated as
+// the Things are marshaled.  With -wrap-list, the Things are written as a
+// single ZSON list value, which is built in memory.
+func bulk(w io.Writer, style zson.TypeStyle, args []string) error {
+	fs := flag.NewFlagSet("bulk", flag.ExitOnError)
+	count := fs.Int("count", 1000, "number of Things to marshal")
+	seed := fs.Int64("seed", 1, "seed for the random choice of Things")
+	timeout := fs.Duration("timeout", 0, "stop after `duration` (0 means no limit)")
+	wrapList := fs.Bool("wrap-list", false, "write the Things as one ZSON list value instead of one value per line")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		usage()
+	}
+	if *count < 0 {
+		return fmt.Errorf("bulk: -count must not be negative: %d", *count)
+	}
+	if outPath == "" {
+		w = ioutil.Discard
+	}
+	names := things.Names()
+	rng := rand.New(rand.NewSource(*seed))
+	next := func(int) (things.Thing, error) {
+		return things.Make(names[rng.Intn(len(names))])
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	cw := &countingWriter{w: bw}
+	start := time.Now()
+	report := func(n int) {
+		elapsed := time.Since(start)
+		fmt.Fprintf(info, "%d things, %d bytes in %s (%.0f things/sec)\n",
+			n, cw.n, elapsed, float64(n)/elapsed.Seconds())
+	}
+	if *wrapList {
+		// A single value is built in memory and cannot be cut short,
+		// so neither -timeout nor an interrupt applies.
+		ts := make([]things.Thing, 0, *count)
+		for k := 0; k < *count; k++ {
+			thing, err := next(k)
+			if err != nil {
+				return err
+			}
+			ts = append(ts, thing)
+		}
+		s, err := things.MarshalThings(newMarshaler(style), ts)
+		if err == nil {
+			err = things.WriteValue(cw, s)
+		}
+		if err != nil {
+			return err
+		}
+		report(len(ts))
+		return nil
+	}
+	enc := things.NewEncoderWith(cw, marshalerFunc(style))
+	showProgress := !quiet && isTerminal(os.Stderr) && !isTerminal(os.Stdout)
+	if showProgress {
+		enc.SetProgress(progressInterval, func(processed int) {
+			fmt.Fprintf(info, "\r%d of %d things", processed, *count)
+		})
+	}
+	n, err := things.EncodeFuncCtx(ctx, enc, *count, next)
+	if flushErr := bw.Flush(); err == nil {
+		err = flushErr
+	}
+	if showProgress && n >= progressInterval {
+		fmt.Fprintln(info)
+	}
+	report(n)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("bulk: timed out after %s with %d of %d things marshaled: %w", *timeout, n, *count, err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("bulk: interrupted with %d of %d things marshaled: %w", n, *count, err)
+	}
+	return err
+}
+
+// interruptContext returns a context that is canceled on the first
+// SIGINT.  The handler is then removed, so a second SIGINT kills the
+// process as usual.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	go func() {
+		select {
+		case <-ch:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(ch)
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// bulkOutput returns the stream bulk writes with args.
+func bulkOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := bulk(&b, zson.StyleSimple, args); err != nil {
+		t.Fatalf("%v: %s", args, err)
+	}
+	return b.String()
+}
+
+func TestBulkSeed(t *testing.T) {
+	info = &bytes.Buffer{}
+	outPath = "bulk.zson"
+	defer func() { outPath = "" }()
+	tests := []struct {
+		a, b []string
+		same bool
+	}{
+		{[]string{"-count", "100"}, []string{"-count", "100"}, true},
+		{[]string{"-count", "100"}, []string{"-count", "100", "-seed", "1"}, true},
+		{[]string{"-count", "100", "-seed", "42"}, []string{"-count", "100", "-seed", "42"}, true},
+		{[]string{"-count", "100", "-seed", "42"}, []string{"-count", "100", "-seed", "43"}, false},
+	}
+	for _, tc := range tests {
+		a, b := bulkOutput(t, tc.a...), bulkOutput(t, tc.b...)
+		if a == "" {
+			t.Fatalf("%v: no output", tc.a)
+		}
+		if (a == b) != tc.same {
+			t.Errorf("%v and %v: identical output is %t, want %t", tc.a, tc.b, a == b, tc.same)
+		}
+	}
+}
+
+func TestBulkWrapList(t *testing.T) {
+	info = &bytes.Buffer{}
+	outPath = "bulk.zson"
+	defer func() { outPath = "" }()
+	var decoded []string
+	for _, args := range [][]string{
+		{"-count", "50", "-seed", "7"},
+		{"-count", "50", "-seed", "7", "-wrap-list"},
+	} {
+		var b bytes.Buffer
+		if err := bulk(&b, zson.StyleSimple, args); err != nil {
+			t.Fatalf("%v: %s", args, err)
+		}
+		var out bytes.Buffer
+		if err := decode(&out, &b, nil); err != nil {
+			t.Fatalf("%v: %s", args, err)
+		}
+		decoded = append(decoded, out.String())
+	}
+	if decoded[0] != decoded[1] {
+		t.Errorf("the list decodes differently from the lines:\n%s\n%s", decoded[1], decoded[0])
+	}
+	if n := bytes.Count([]byte(decoded[0]), []byte("\n")); n != 50 {
+		t.Errorf("decoded %d Things, want 50", n)
+	}
+}
+
+func TestBulkCount(t *testing.T) {
+	info = &bytes.Buffer{}
+	outPath = "bulk.zson"
+	defer func() { outPath = "" }()
+	tests := []struct {
+		args  []string
+		lines int
+		fail  bool
+	}{
+		{[]string{"-count", "0"}, 0, false},
+		{[]string{"-count", "0", "-wrap-list"}, 1, false},
+		{[]string{"-count", "3"}, 3, false},
+		{[]string{"-count", "3", "-wrap-list"}, 1, false},
+		{[]string{"-count", "-1"}, 0, true},
+		{[]string{"-count", "-1", "-wrap-list"}, 0, true},
+	}
+	for _, tc := range tests {
+		var b bytes.Buffer
+		err := bulk(&b, zson.StyleSimple, tc.args)
+		if tc.fail {
+			if err == nil {
+				t.Errorf("%v: no error", tc.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: %s", tc.args, err)
+		}
+		if n := bytes.Count(b.Bytes(), []byte("\n")); n != tc.lines {
+			t.Errorf("%v: wrote %d lines, want %d", tc.args, n, tc.lines)
+		}
+	}
+}
+
+func TestBulkTimeout(t *testing.T) {
+	info = &bytes.Buffer{}
+	err := bulk(&bytes.Buffer{}, zson.StyleSimple, []string{"-count", "100000000", "-timeout", "10ms"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a deadline error", err)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "bulk: timed out after 10ms with ") || !strings.Contains(msg, " of 100000000 things marshaled") {
+		t.Errorf("got %q, want the partial count", msg)
+	}
+	_, stderr, err := runMain(t, "bulk", "-count", "100000000", "-timeout", "1ms")
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != exitTimeout {
+		t.Errorf("got %v, want exit status %d", err, exitTimeout)
+	}
+	if !strings.Contains(stderr, "bulk: timed out after 1ms with ") {
+		t.Errorf("stderr does not report the timeout:\n%s", stderr)
+	}
+}
+
+func TestBulkInterrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bulk.zson")
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), "ZMARSHAL_TEST_ARGS=-o "+path+" bulk -count 100000000")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Output reaches the file once the first buffer fills, by which
+	// time bulk is handling interrupts.
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if fi, err := os.Stat(path); err == nil && fi.Size() > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			t.Fatal("bulk wrote nothing")
+		}
+	}
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		cmd.Process.Kill()
+		t.Skipf("cannot interrupt: %s", err)
+	}
+	err := cmd.Wait()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != exitInterrupt {
+		t.Fatalf("got %v, want exit status %d\n%s", err, exitInterrupt, stderr.String())
+	}
+	var n int
+	msg := stderr.String()
+	if k := strings.Index(msg, "bulk: interrupted with "); k < 0 {
+		t.Fatalf("stderr does not report the interrupt:\n%s", msg)
+	} else if _, err := fmt.Sscanf(msg[k:], "bulk: interrupted with %d of 100000000 things marshaled", &n); err != nil {
+		t.Fatalf("%s in %q", err, msg[k:])
+	}
+	// Every Thing counted as marshaled was flushed to the file whole.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(b, []byte("\n")); n == 0 || lines != n || b[len(b)-1] != '\n' {
+		t.Errorf("file holds %d lines for %d things marshaled", lines, n)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// canonicalize reads a stream of decorated Things from r in any ZSON
+// formatting and writes each to w in compact form, one per line, so that
+// streams holding the same Things are byte-identical.  It ignores -pretty.
+func canonicalize(w io.Writer, r io.Reader, style zson.TypeStyle) error {
+	dec := things.NewDecoderWith(r, newUnmarshaler())
+	enc := things.NewEncoderWith(w, marshalerFuncIndent(style, 0))
+	for k := 0; ; k++ {
+		thing, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			err = enc.Encode(thing)
+		}
+		if err != nil {
+			return fmt.Errorf("canonicalize: value %d %s: %w", k, dec.Position(), err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// TestCanonicalize feeds canonicalize the same Things formatted in
+// different ways and checks each against the compact golden file.
+func TestCanonicalize(t *testing.T) {
+	info = &bytes.Buffer{}
+	defer func() { pretty = false }()
+	garden := &things.Garden{MyName: "backyard", Contents: things.List{things.MustMake("rose"), things.MustMake("flamingo")}}
+	values := []things.Thing{things.MustMake("quartz"), garden, things.MustMake("ivy")}
+	pretty = true
+	indented := marshalStream(t, values)
+	pretty = false
+	compact := marshalStream(t, values)
+	lines := strings.Split(compact, "\n")
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"compact", compact},
+		{"pretty", indented},
+		{"hand formatted", `// a comment
+{ MyColor : "white" , MyName:"quartz",
+  Hardness: 7 } (=Mineral)
+
+` + lines[1] + `   {BaseThing:{ color:"green"} (=BaseThing),
+	MyName: "ivy" /* a comment */ }
+	(=Plant)
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := capture(t, func() error {
+				return canonicalize(out, strings.NewReader(tc.input), zson.StyleSimple)
+			})
+			checkGolden(t, "canonical.zson", got)
+		})
+	}
+}
+
+// marshalStream returns ts marshaled in simple style, one value per
+// line or, with -pretty, one after another over several lines.
+func marshalStream(t *testing.T, ts []things.Thing) string {
+	t.Helper()
+	var b strings.Builder
+	for _, thing := range ts {
+		s, err := marshal(zson.StyleSimple, thing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString(s + "\n")
+	}
+	return b.String()
+}
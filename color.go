@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// noColor disables ANSI color output; see the -no-color flag.
+var noColor bool
+
+var ansiColors = map[string]string{
+	"blue":  "\x1b[34m",
+	"green": "\x1b[32m",
+	"pink":  "\x1b[95m",
+	"red":   "\x1b[31m",
+	"white": "\x1b[97m",
+}
+
+const ansiReset = "\x1b[0m"
+
+// useColor reports whether output to w should be colored, which is only
+// when w is a terminal and -no-color was not given.
+func useColor(w io.Writer) bool {
+	return !noColor && isTerminal(w)
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// paint renders the first occurrence of color in s in that color when
+// w is a terminal.  Colors without an ANSI equivalent are left as is.
+func paint(w io.Writer, s, color string) string {
+	code, ok := ansiColors[color]
+	if !ok || !useColor(w) {
+		return s
+	}
+	return strings.Replace(s, color, code+color+ansiReset, 1)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNoColor(t *testing.T) {
+	info = &bytes.Buffer{}
+	defer func() { noColor = false }()
+	in := `{BaseThing:{color:"red"}(=BaseThing),MyName:"rose"}(=Plant)` + "\n" +
+		`{BaseThing:{color:"pink"}(=BaseThing),MyName:"flamingo"}(=Animal)` + "\n"
+	for _, nc := range []bool{false, true} {
+		noColor = nc
+		var out bytes.Buffer
+		if err := decode(&out, strings.NewReader(in), nil); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(out.String(), "\x1b") {
+			t.Errorf("no-color %t: output to a buffer has escape sequences: %q", nc, out.String())
+		}
+	}
+	// paint only checks whether its writer is a terminal, so a terminal
+	// serves here without anything being written to it.
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("no terminal: %s", err)
+	}
+	defer tty.Close()
+	tests := []struct {
+		noColor bool
+		want    string
+	}{
+		{false, "Plant(\x1b[31mred\x1b[0m)"},
+		{true, "Plant(red)"},
+	}
+	for _, tc := range tests {
+		noColor = tc.noColor
+		if got := paint(tty, "Plant(red)", "red"); got != tc.want {
+			t.Errorf("no-color %t: got %q, want %q", tc.noColor, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+// compare writes each named Thing marshaled under each style.  With no
+// names, it compares every registered Thing.
+func compare(w io.Writer, names []string) error {
+	if len(names) == 0 {
+		names = things.Names()
+	}
+	for _, name := range names {
+		thing, err := things.Make(name)
+		if err != nil {
+			return err
+		}
+		if len(names) > 1 {
+			fmt.Fprintln(w, name)
+		}
+		for _, s := range things.Styles() {
+			out, err := marshal(s.Style, thing)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%-8s %s\n", s.Name+":", out)
+		}
+	}
+	return nil
+}
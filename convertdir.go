@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// convertDir re-marshals every .zson file in the directory inDir into a
+// file of the same name in outDir, which is created if need be, using
+// the given style or that of a -style flag following the directories.
+// Other files are skipped.  Each file converted is reported to w.  A
+// file that fails, including one holding anything but well-formed values,
+// such as a truncated last value, is reported to info instead and leaves
+// no output file, and the rest are still converted.  An interrupt stops
+// it between files.
+func convertDir(w io.Writer, style zson.TypeStyle, args []string) error {
+	fs := flag.NewFlagSet("convert-dir", flag.ExitOnError)
+	styleFlag := fs.String("style", "", "decoration style ("+styleNames()+")")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		usage()
+	}
+	inDir, outDir := fs.Arg(0), fs.Arg(1)
+	fs.Parse(fs.Args()[2:])
+	if fs.NArg() != 0 {
+		usage()
+	}
+	if *styleFlag != "" {
+		var err error
+		if style, err = parseStyle(*styleFlag); err != nil {
+			return err
+		}
+	}
+	paths, err := filepath.Glob(filepath.Join(inDir, "*.zson"))
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		if err := os.MkdirAll(outDir, 0777); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	var failed, converted, values, size int
+	for k, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("convert-dir: interrupted with %d of %d files converted: %w", k-failed, len(paths), err)
+		}
+		name := filepath.Base(path)
+		n, written, err := convertFile(path, filepath.Join(outDir, name), style)
+		if err != nil {
+			fmt.Fprintf(info, "%s: %s\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "%s: ok (%d values, %d bytes)\n", name, n, written)
+		converted++
+		values += n
+		size += written
+	}
+	if dryRun {
+		fmt.Fprintf(info, "dry run: would have written %d bytes in %d values to %d files in %s\n", size, values, converted, outDir)
+	}
+	if failed > 0 {
+		return fmt.Errorf("convert-dir: %d of %d files failed", failed, len(paths))
+	}
+	return nil
+}
+
+// convertFile re-marshals the Things in the file inPath with style and
+// writes them to outPath, which is written only if every value converts
+// and -dry-run was not given.  It returns the number of values converted
+// and the size of their marshaled form, which is the same whether or not
+// it was written.
+func convertFile(inPath, outPath string, style zson.TypeStyle) (int, int, error) {
+	f, err := os.Open(inPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	dec := things.NewDecoderWith(limitReader(f, defaultMaxBytes), newUnmarshaler())
+	enc := things.NewEncoderWith(&buf, marshalerFunc(style))
+	for k := 0; ; k++ {
+		thing, err := dec.Decode()
+		if err == io.EOF {
+			if dryRun {
+				return k, buf.Len(), nil
+			}
+			return k, buf.Len(), ioutil.WriteFile(outPath, buf.Bytes(), 0666)
+		}
+		if err == nil {
+			err = enc.Encode(thing)
+		}
+		if err != nil {
+			return k, 0, fmt.Errorf("value %d %s: %w", k, dec.Position(), err)
+		}
+	}
+}
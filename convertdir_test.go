@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestConvertDir(t *testing.T) {
+	in, out := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	quartz := `{MyColor:"white",MyName:"quartz",Hardness:7}(=Mineral)` + "\n"
+	files := []struct {
+		name    string
+		content string
+		status  string
+	}{
+		{"one.zson", quartz, "one.zson: ok (1 values, 62 bytes)"},
+		{"two.zson", quartz + quartz, "two.zson: ok (2 values, 124 bytes)"},
+		{"junk.zson", "junk\n", "junk.zson: value 0"},
+		{"truncated.zson", quartz + `{a:`, "truncated.zson: value 1"},
+		{"notes.txt", "not ZSON", ""},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(in, f.name), []byte(f.content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := info
+	t.Cleanup(func() { info = saved })
+	var results, failures bytes.Buffer
+	info = &failures
+	err := convertDir(&results, zson.StylePackage, []string{in, out})
+	if err == nil || err.Error() != "convert-dir: 2 of 4 files failed" {
+		t.Errorf("got error %v, want 2 of 4 files failed", err)
+	}
+	for _, f := range files {
+		_, statErr := os.Stat(filepath.Join(out, f.name))
+		if f.status == "" {
+			if strings.Contains(results.String()+failures.String(), f.name) || statErr == nil {
+				t.Errorf("%s was not skipped", f.name)
+			}
+			continue
+		}
+		// Conversions are reported with the results and failures
+		// apart from them.
+		ok := strings.Contains(f.status, ": ok")
+		report, other := &results, &failures
+		if !ok {
+			report, other = other, report
+		}
+		if !strings.Contains(report.String(), f.status) || strings.Contains(other.String(), f.name) {
+			t.Errorf("%q is not reported apart from the others:\nresults:\n%sfailures:\n%s", f.status, &results, &failures)
+		}
+		if ok != (statErr == nil) {
+			t.Errorf("%s: output file exists is %t, want %t", f.name, statErr == nil, ok)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(out, "one.zson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(b)), "(=things.Mineral)") {
+		t.Errorf("one.zson was not converted to StylePackage: %s", b)
+	}
+}
+
+func TestConvertDirDryRun(t *testing.T) {
+	in, out := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	quartz := `{MyColor:"white",MyName:"quartz",Hardness:7}(=Mineral)` + "\n"
+	for name, content := range map[string]string{"one.zson": quartz, "two.zson": quartz + quartz} {
+		if err := os.WriteFile(filepath.Join(in, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stdout, stderr, err := runMain(t, "-dry-run", "convert-dir", in, out, "-style", "package")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("wrote %q to stdout", stdout)
+	}
+	// The sizes are those of the converted files, not of the report.
+	if want := "dry run: would have written 186 bytes in 3 values to 2 files in " + out + "\n"; stderr != want {
+		t.Errorf("stderr is %q, want %q", stderr, want)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("output directory was created: %v", err)
+	}
+}
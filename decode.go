@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/brimdata/zed"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// decode reads decorated Things from the file named in args, or from r
+// if no file is given, and writes a description of each to w.  With
+// -columns, it writes a table of their types, names, and colors instead,
+// and with -select, just the named field.  With -flatten, the Things in
+// nested Gardens are printed in place of the Gardens.  With -limit, it
+// stops reading once it has printed that many Things.  A ZSON list of
+// Things is read as a stream of its elements, though -strict checks only
+// values that are not lists.  Input that holds no value, whether empty
+// or only whitespace, is an error.
+func decode(w io.Writer, r io.Reader, args []string) (err error) {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	strict := fs.Bool("strict", false, "reject input fields the Thing's type does not have")
+	typeName := fs.String("type", "", "print the Thing only if its type has this name")
+	maxBytes := fs.Int64("max-bytes", defaultMaxBytes, "fail if the input is larger than `n` bytes")
+	nullAsEmpty := fs.Bool("null-as-empty", false, "decode null *string fields as empty strings rather than nil")
+	columns := fs.Bool("columns", false, "print a TYPE, NAME, COLOR table instead of descriptions")
+	flatten := fs.Bool("flatten", false, "print the Things within Gardens in place of the Gardens")
+	selectField := fs.String("select", "", "print only this `field` of each Thing (color, name)")
+	limit := fs.Int("limit", 0, "stop after printing `n` Things (0 means no limit)")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		usage()
+	}
+	if *maxBytes < 0 {
+		return fmt.Errorf("decode: -max-bytes must not be negative: %d", *maxBytes)
+	}
+	keep, err := typeFilter(*typeName)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	if path := fs.Arg(0); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	logBindings()
+	show := func(t things.Thing) {
+		fmt.Fprintln(w, paint(w, things.Describe(t), t.Color()))
+	}
+	if *selectField != "" {
+		if *columns {
+			return errors.New("decode: -select cannot be combined with -columns")
+		}
+		field, ok := selectors[*selectField]
+		if !ok {
+			return fmt.Errorf("decode: unknown -select field %q (valid fields: color, name)", *selectField)
+		}
+		show = func(t things.Thing) {
+			fmt.Fprintln(w, field(t))
+		}
+	}
+	if *columns {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TYPE\tNAME\tCOLOR")
+		// Flush however decode returns, so that the rows of the
+		// Things decoded before an error are still written.
+		defer func() {
+			if flushErr := tw.Flush(); err == nil {
+				err = flushErr
+			}
+		}()
+		show = func(t things.Thing) {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", thingType(t), t.Name(), t.Color())
+		}
+	}
+	u := newUnmarshaler()
+	cr := &countingReader{r: limitReader(r, *maxBytes)}
+	reader := things.NewValueReader(cr, zed.NewContext())
+	var values, shown int
+	fail := func(err error) error {
+		return fmt.Errorf("decode: value %d %s: %w", values, reader.Position(), err)
+	}
+	for {
+		if *limit > 0 && shown == *limit {
+			fmt.Fprintf(info, "decode: stopped at the limit of %d values\n", *limit)
+			break
+		}
+		val, err := reader.Read()
+		if err != nil {
+			return fail(err)
+		}
+		if val == nil {
+			break
+		}
+		var list things.List
+		if _, ok := zed.TypeUnder(val.Type).(*zed.TypeArray); ok {
+			// A list of Things, as written by bulk -wrap-list, is
+			// taken as a stream of its elements.
+			if err := things.UnmarshalValue(u, val, &list); err != nil {
+				return fail(err)
+			}
+			for k, thing := range list {
+				if thing == nil {
+					return fail(fmt.Errorf("%w: element %d is null, not a Thing", things.ErrBadInput, k))
+				}
+			}
+		} else {
+			var thing things.Thing
+			if err := things.UnmarshalValue(u, val, &thing); err != nil {
+				return fail(err)
+			}
+			if thing == nil {
+				return fail(fmt.Errorf("%w: null is not a Thing", things.ErrBadInput))
+			}
+			if *strict {
+				if err := things.CheckValueFields(val, thing); err != nil {
+					return fail(err)
+				}
+			}
+			list = []things.Thing{thing}
+		}
+		values++
+		if *flatten {
+			var flat []things.Thing
+			for _, thing := range list {
+				flat = append(flat, things.Flatten(thing)...)
+			}
+			list = flat
+		}
+		for _, thing := range list {
+			if *limit > 0 && shown == *limit {
+				break
+			}
+			if *nullAsEmpty {
+				things.NullAsEmpty(thing)
+			}
+			verbosef("decoded %T\n", thing)
+			if keep(thing) {
+				show(thing)
+				shown++
+			}
+		}
+	}
+	verbosef("read %d bytes\n", cr.n)
+	if values == 0 {
+		if cr.n == 0 {
+			return errors.New("decode: no value to decode: input is empty")
+		}
+		return errors.New("decode: no value to decode: input is only whitespace")
+	}
+	return nil
+}
+
+// selectors are the fields that decode -select can print.
+var selectors = map[string]func(things.Thing) string{
+	"color": things.Thing.Color,
+	"name":  things.Thing.Name,
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// diffThings writes a line-oriented diff of the ZSON of the Things named
+// a and b, marshaled with the same style.  Nothing is written if they
+// marshal identically.  With -pretty, the diff is of the values
+// pretty-printed; see prettyDiff.
+func diffThings(w io.Writer, style zson.TypeStyle, a, b string) error {
+	ta, err := things.Make(a)
+	if err != nil {
+		return err
+	}
+	tb, err := things.Make(b)
+	if err != nil {
+		return err
+	}
+	sa, err := newMarshalerIndent(style, 0).Marshal(ta)
+	if err != nil {
+		return err
+	}
+	sb, err := newMarshalerIndent(style, 0).Marshal(tb)
+	if err != nil {
+		return err
+	}
+	_, err = prettyDiff(w, a, b, sa, sb)
+	return err
+}
+
+// prettyDiff is like unifiedDiff for the ZSON values a and b, but with
+// -pretty it first formats both over multiple lines, one field to a line,
+// so that the diff shows just the fields that differ instead of one
+// changed line for each whole value.
+func prettyDiff(w io.Writer, aName, bName, a, b string) (bool, error) {
+	if a == b {
+		return false, nil
+	}
+	if pretty {
+		var err error
+		if a, err = prettyFormat(a); err != nil {
+			return false, err
+		}
+		if b, err = prettyFormat(b); err != nil {
+			return false, err
+		}
+	}
+	return unifiedDiff(w, aName, bName, a, b), nil
+}
+
+// prettyFormat reformats the ZSON value s as -pretty output is formatted.
+func prettyFormat(s string) (string, error) {
+	zv, err := zson.ParseValue(zed.NewContext(), s)
+	if err != nil {
+		return "", err
+	}
+	return zson.NewFormatter(prettyIndent, nil).Format(zv)
+}
+
+// unifiedDiff writes a line-oriented diff of a and b to w in the style
+// of diff -u, without hunk headers.  It returns true if a and b differ.
+func unifiedDiff(w io.Writer, aName, bName, a, b string) bool {
+	if a == b {
+		return false
+	}
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+	// lcs[i][j] is the length of the longest common subsequence
+	// of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", aName, bName)
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(w, " %s\n", x[i])
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(w, "-%s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(w, "+%s\n", y[j])
+			j++
+		}
+	}
+	return true
+}
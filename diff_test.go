@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestDiffThings(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		empty bool
+	}{
+		{"rose", "rose", true},
+		{"flamingo", "flamingo", true},
+		{"rose", "flamingo", false},
+		{"rose", "ivy", false},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		if err := diffThings(&buf, zson.StyleSimple, tc.a, tc.b); err != nil {
+			t.Fatalf("%s vs %s: %s", tc.a, tc.b, err)
+		}
+		if empty := buf.Len() == 0; empty != tc.empty {
+			t.Errorf("%s vs %s: got diff %q", tc.a, tc.b, buf.String())
+		}
+	}
+	if err := diffThings(&bytes.Buffer{}, zson.StyleSimple, "rose", "nonesuch"); err == nil {
+		t.Error("diff with an unknown name succeeded")
+	}
+}
+
+func TestPrettyDiff(t *testing.T) {
+	white, err := marshal(zson.StyleSimple, &things.Mineral{MyColor: "white", MyName: "quartz", Hardness: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pink, err := marshal(zson.StyleSimple, &things.Mineral{MyColor: "pink", MyName: "quartz", Hardness: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pretty  bool
+		removed string
+		added   string
+	}{
+		{false, white, pink},
+		{true, `    MyColor: "white",`, `    MyColor: "pink",`},
+	}
+	defer func() { pretty = false }()
+	for _, tc := range tests {
+		pretty = tc.pretty
+		var buf bytes.Buffer
+		changed, err := prettyDiff(&buf, "a", "b", white, pink)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !changed {
+			t.Fatalf("pretty %t: no difference found", tc.pretty)
+		}
+		var removed, added []string
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			case strings.HasPrefix(line, "-"):
+				removed = append(removed, line[1:])
+			case strings.HasPrefix(line, "+"):
+				added = append(added, line[1:])
+			}
+		}
+		if len(removed) != 1 || removed[0] != tc.removed || len(added) != 1 || added[0] != tc.added {
+			t.Errorf("pretty %t: got diff\n%s", tc.pretty, buf.String())
+		}
+	}
+}
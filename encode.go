@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+type spec struct {
+	Which string `zed:"which"`
+}
+
+// encode reads a ZSON record of the form {which:"flamingo"} from r, makes
+// the named Thing, and writes its decorated ZSON to w.
+func encode(w io.Writer, r io.Reader, style zson.TypeStyle) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var s spec
+	if err := zson.Unmarshal(string(b), &s); err != nil {
+		return err
+	}
+	if s.Which == "" {
+		return errors.New("encode: input record is missing the which field")
+	}
+	thing, err := things.Make(s.Which)
+	if err != nil {
+		return err
+	}
+	out, err := marshal(style, thing)
+	if err != nil {
+		return err
+	}
+	return things.WriteValue(w, out)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestExamples compares the output of each example, run with its own
+// style, with the golden file testdata/exN.zson.  Run go test -update to
+// rewrite the golden files after an intended change.
+func TestExamples(t *testing.T) {
+	for _, ex := range examples {
+		got := capture(t, func() error { return ex.run(ex.style) })
+		checkGolden(t, fmt.Sprintf("ex%d.zson", ex.num), got)
+	}
+}
+
+// TestPretty pins the compact and pretty forms of the Garden example,
+// the latter at indentation widths of 2 and 4.
+func TestPretty(t *testing.T) {
+	defer func() { pretty, prettyIndent = false, 4 }()
+	tests := []struct {
+		pretty bool
+		indent int
+		golden string
+	}{
+		{false, 4, "ex8.zson"},
+		{true, 2, "ex8-indent2.zson"},
+		{true, 4, "ex8-pretty.zson"},
+	}
+	for _, tc := range tests {
+		pretty, prettyIndent = tc.pretty, tc.indent
+		got := capture(t, func() error { return ex8(zson.StyleSimple) })
+		checkGolden(t, tc.golden, got)
+	}
+}
+
+// checkGolden compares got with the golden file testdata/name, or
+// rewrites the file with got under -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: got\n%swant\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"text/tabwriter"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+// fields writes the exported fields of the concrete type of the named
+// Thing with their Go types and struct tags.  Fields of nested structs,
+// including embedded ones, which zson marshals as nested records, are
+// listed under dotted paths such as BaseThing.MyColor.
+func fields(w io.Writer, name string) error {
+	thing, err := things.Make(name)
+	if err != nil {
+		return err
+	}
+	typ := reflect.TypeOf(thing)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tTYPE\tTAG")
+	writeFields(tw, "", typ)
+	return tw.Flush()
+}
+
+func writeFields(w io.Writer, prefix string, typ reflect.Type) {
+	for k := 0; k < typ.NumField(); k++ {
+		f := typ.Field(k)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := string(f.Tag)
+		if tag == "" {
+			tag = "-"
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%s\n", prefix, f.Name, f.Type, tag)
+		if f.Type.Kind() == reflect.Struct {
+			writeFields(w, prefix+f.Name+".", f.Type)
+		}
+	}
+}
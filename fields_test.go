@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"rose", `FIELD              TYPE              TAG
+BaseThing          things.BaseThing  -
+BaseThing.MyColor  string            zed:"color"
+MyName             string            -
+`},
+		{"quartz", `FIELD     TYPE    TAG
+MyColor   string  -
+MyName    string  -
+Hardness  int     -
+`},
+	}
+	for _, tc := range tests {
+		var b bytes.Buffer
+		if err := fields(&b, tc.name); err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if b.String() != tc.want {
+			t.Errorf("%s: got\n%swant\n%s", tc.name, b.String(), tc.want)
+		}
+	}
+	if err := fields(&bytes.Buffer{}, "unicorn"); !errors.Is(err, things.ErrUnknownThing) {
+		t.Errorf("unicorn: got %v, want an unknown thing error", err)
+	}
+}
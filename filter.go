@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+// typeFilter returns a predicate matching Things whose concrete type is
+// named name, or a predicate matching everything if name is empty.  The
+// name must be one of the registered types.
+func typeFilter(name string) (func(things.Thing) bool, error) {
+	if name == "" {
+		return func(things.Thing) bool { return true }, nil
+	}
+	known := things.TypeNames()
+	for _, n := range known {
+		if n == name {
+			return func(t things.Thing) bool {
+				return thingType(t) == name
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown type %q (registered types: %s)", name, strings.Join(known, ", "))
+}
+
+// thingType returns the name of the concrete type of t without any
+// pointer, e.g., Plant.
+func thingType(t things.Thing) string {
+	typ := reflect.TypeOf(t)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Name()
+}
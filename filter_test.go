@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestTypeFilter(t *testing.T) {
+	info = &bytes.Buffer{}
+	in := stream(t, zson.StyleSimple, "rose", "flamingo", "ivy", "quartz", "emerald")
+	tests := []struct {
+		typ  string
+		want []string
+	}{
+		{"", []string{"rose", "flamingo", "ivy", "quartz", "emerald"}},
+		{"Plant", []string{"rose", "ivy"}},
+		{"Animal", []string{"flamingo"}},
+		{"Gem", []string{"emerald"}},
+		{"Garden", nil},
+	}
+	for _, tc := range tests {
+		var args []string
+		if tc.typ != "" {
+			args = []string{"-type", tc.typ}
+		}
+		var out bytes.Buffer
+		if err := roundtrip(&out, strings.NewReader(in), zson.StyleSimple, args); err != nil {
+			t.Fatalf("roundtrip %v: %s", args, err)
+		}
+		if want := stream(t, zson.StyleSimple, tc.want...); out.String() != want {
+			t.Errorf("roundtrip %v: got\n%swant\n%s", args, out.String(), want)
+		}
+		out.Reset()
+		if err := decode(&out, strings.NewReader(in), append(args, "-select", "name")); err != nil {
+			t.Fatalf("decode %v: %s", args, err)
+		}
+		var names string
+		for _, name := range tc.want {
+			names += name + "\n"
+		}
+		if out.String() != names {
+			t.Errorf("decode %v: got %q, want %q", args, out.String(), names)
+		}
+	}
+	// An unknown type is an error before any input is read, so it is
+	// not mistaken for empty input.
+	for _, cmd := range []func() error{
+		func() error {
+			return roundtrip(&bytes.Buffer{}, strings.NewReader(""), zson.StyleSimple, []string{"-type", "Unicorn"})
+		},
+		func() error { return decode(&bytes.Buffer{}, strings.NewReader(""), []string{"-type", "Unicorn"}) },
+	} {
+		if err := cmd(); err == nil || !strings.Contains(err.Error(), `unknown type "Unicorn"`) {
+			t.Errorf("got %v, want an unknown type error", err)
+		}
+	}
+}
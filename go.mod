@@ -1,10 +1,12 @@
-module zmarshal
+module github.com/mccanne/zmarshal
 
-go 1.15
+go 1.18
 
 require (
-	github.com/brimdata/zed v1.1.0 // indirect
+	github.com/brimdata/zed v1.1.0
 	github.com/brimsec/zq v0.26.0
 )
 
+require golang.org/x/text v0.3.4 // indirect
+
 replace github.com/brimsec/zq => github.com/brimsec/zq v0.26.1-0.20201225202527-d739c0744d4f
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// importJSON reads a JSON array of {"which": name} objects from r and
+// writes the corresponding decorated Things to w as a ZSON stream.
+func importJSON(w io.Writer, r io.Reader, style zson.TypeStyle) error {
+	var specs []struct {
+		Which string `json:"which"`
+	}
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+	for k, spec := range specs {
+		thing, err := things.Make(spec.Which)
+		if err != nil {
+			return fmt.Errorf("import: element %d: %w", k, err)
+		}
+		s, err := marshal(style, thing)
+		if err != nil {
+			return fmt.Errorf("import: element %d: %w", k, err)
+		}
+		if err := things.WriteValue(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+		err   string
+	}{
+		{"empty array", `[]`, nil, ""},
+		{"two Things", `[{"which":"rose"},{"which":"flamingo"}]`, []string{"rose", "flamingo"}, ""},
+		{"unknown Thing", `[{"which":"rose"},{"which":"unicorn"}]`, nil, `element 1: unknown thing "unicorn"`},
+		{"not an array", `{"which":"rose"}`, nil, "import: json"},
+	}
+	for _, tc := range tests {
+		var out bytes.Buffer
+		err := importJSON(&out, strings.NewReader(tc.input), zson.StyleSimple)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: got %v, want an error containing %q", tc.name, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		var want string
+		for _, name := range tc.want {
+			s, err := marshal(zson.StyleSimple, things.MustMake(name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want += s + "\n"
+		}
+		if out.String() != want {
+			t.Errorf("%s: got\n%swant\n%s", tc.name, out.String(), want)
+		}
+	}
+	err := importJSON(&bytes.Buffer{}, strings.NewReader(`[{"which":"unicorn"}]`), zson.StyleSimple)
+	if !errors.Is(err, things.ErrUnknownThing) {
+		t.Errorf("unknown Thing: got %v, want an error wrapping ErrUnknownThing", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// format is the output encoding selected by the -format flag.
+var format = "zson"
+
+// output writes v to w in the selected format.  JSON has no way to
+// represent ZSON decorators, so unless style is StyleNone, each Thing
+// is rendered as a JSON object with an added _type field holding the
+// name of its concrete Go type.
+func output(w io.Writer, style zson.TypeStyle, v interface{}) error {
+	if format != "json" {
+		s, err := marshal(style, v)
+		if err != nil {
+			return err
+		}
+		return things.WriteValue(w, s)
+	}
+	if style != zson.StyleNone {
+		var err error
+		if v, err = typedJSON(v); err != nil {
+			return err
+		}
+	}
+	var b []byte
+	var err error
+	if pretty {
+		b, err = json.MarshalIndent(v, "", strings.Repeat(" ", prettyIndent))
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	return things.WriteValue(w, string(b))
+}
+
+func typedJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case things.Thing:
+		return typedThing(v)
+	case things.List:
+		return typedJSON([]things.Thing(v))
+	case []things.Thing:
+		out := make([]interface{}, 0, len(v))
+		for _, thing := range v {
+			obj, err := typedThing(thing)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, obj)
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func typedThing(thing things.Thing) (map[string]interface{}, error) {
+	b, err := json.Marshal(thing)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeOf(thing)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	obj["_type"] = typ.Name()
+	return obj, nil
+}
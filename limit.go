@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultMaxBytes is the default for the -max-bytes flag of decode and
+// roundtrip.
+const defaultMaxBytes = 64 << 20
+
+// errTooLarge is the error a reader from limitReader returns once the
+// input goes past its limit.
+var errTooLarge = errors.New("input exceeds the limit set by -max-bytes")
+
+// limitReader returns a reader that yields at most max bytes of r and
+// then fails with errTooLarge if r has more, so input that was cut short
+// is reported instead of being mistaken for a clean EOF.
+func limitReader(r io.Reader, max int64) io.Reader {
+	return &limitedReader{r: r, max: max, remaining: max}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	max       int64
+	remaining int64
+	err       error
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if l.remaining <= 0 {
+		// Read past the limit only to tell a clean EOF from more input.
+		var one [1]byte
+		n, err := l.r.Read(one[:])
+		if n > 0 {
+			l.err = fmt.Errorf("%w (%d bytes)", errTooLarge, l.max)
+			return 0, l.err
+		}
+		return 0, err
+	}
+	if int64(len(b)) > l.remaining {
+		b = b[:l.remaining]
+	}
+	n, err := l.r.Read(b)
+	l.remaining -= int64(n)
+	return n, err
+}
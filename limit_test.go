@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestLimitReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		max     int64
+		tooLong bool
+	}{
+		{"empty", "", 0, false},
+		{"under", "abc", 4, false},
+		{"at the limit", "abcd", 4, false},
+		{"one over", "abcde", 4, true},
+		{"far over", strings.Repeat("x", 1000), 10, true},
+		{"zero limit", "a", 0, true},
+	}
+	for _, tc := range tests {
+		// A OneByteReader puts the limit between reads rather than
+		// within one.
+		for _, r := range []io.Reader{strings.NewReader(tc.input), iotest.OneByteReader(strings.NewReader(tc.input))} {
+			lr := limitReader(r, tc.max)
+			b, err := ioutil.ReadAll(lr)
+			if !tc.tooLong {
+				if err != nil || string(b) != tc.input {
+					t.Errorf("%s: read %q, %v", tc.name, b, err)
+				}
+				continue
+			}
+			if !errors.Is(err, errTooLarge) {
+				t.Errorf("%s: got %v, want the limit error", tc.name, err)
+			}
+			if int64(len(b)) != tc.max {
+				t.Errorf("%s: read %d bytes before the error, want %d", tc.name, len(b), tc.max)
+			}
+			if n, err := lr.Read(make([]byte, 8)); n != 0 || !errors.Is(err, errTooLarge) {
+				t.Errorf("%s: read after the limit gave %d, %v", tc.name, n, err)
+			}
+		}
+	}
+}
+
+func TestDecodeMaxBytes(t *testing.T) {
+	in := stream(t, zson.StyleSimple, "rose", "flamingo")
+	var out bytes.Buffer
+	if err := decode(&out, strings.NewReader(in), []string{"-max-bytes", "10"}); !errors.Is(err, errTooLarge) {
+		t.Errorf("decode past -max-bytes: got %v, want the limit error", err)
+	}
+	if err := decode(&out, strings.NewReader(in), []string{"-max-bytes", "-1"}); err == nil {
+		t.Error("decode accepted a negative -max-bytes")
+	}
+}
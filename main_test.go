@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain runs main instead of the tests when runMain starts the test
+// binary as a subprocess, so that tests can check what main does with
+// its flags.
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv("ZMARSHAL_TEST_ARGS"); ok {
+		os.Args = append([]string{"zmarshal"}, strings.Fields(args)...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs zmarshal with args in a subprocess and returns what it
+// wrote to stdout and stderr.
+func runMain(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), "ZMARSHAL_TEST_ARGS="+strings.Join(args, " "))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestQuiet(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stdout string
+		stderr string
+		fail   bool
+	}{
+		{[]string{"verify", "rose"}, "rose: ok\n", "", false},
+		{[]string{"-quiet", "verify", "rose"}, "", "", false},
+		{[]string{"-quiet", "verify", "unicorn"}, "", "unknown thing \"unicorn\"\n", true},
+		{[]string{"-quiet", "bulk", "-count", "5"}, "", "", false},
+		{[]string{"-quiet", "-v", "decode", os.DevNull}, "", "bound types: ", true},
+	}
+	for _, tc := range tests {
+		stdout, stderr, err := runMain(t, tc.args...)
+		if (err != nil) != tc.fail {
+			t.Errorf("%v: got error %v, want failure %t", tc.args, err, tc.fail)
+		}
+		if stdout != tc.stdout {
+			t.Errorf("%v: stdout is %q, want %q", tc.args, stdout, tc.stdout)
+		}
+		if !strings.HasPrefix(stderr, tc.stderr) || (tc.stderr == "" && stderr != "") {
+			t.Errorf("%v: stderr is %q, want it to begin %q", tc.args, stderr, tc.stderr)
+		}
+	}
+}
+
+func TestExampleFromEnv(t *testing.T) {
+	golden := func(name string) string {
+		b, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	tests := []struct {
+		env    string
+		args   []string
+		stdout string
+		fail   bool
+	}{
+		{"3", nil, golden("ex3.zson"), false},
+		{"garden", nil, golden("ex8.zson"), false},
+		{"3", []string{"4"}, golden("ex4.zson"), false},
+		{"unicorn", []string{"4"}, golden("ex4.zson"), false},
+		{"unicorn", nil, "", true},
+		{"", nil, "", true},
+	}
+	for _, tc := range tests {
+		t.Setenv("ZMARSHAL_EXAMPLE", tc.env)
+		stdout, stderr, err := runMain(t, tc.args...)
+		if (err != nil) != tc.fail {
+			t.Errorf("ZMARSHAL_EXAMPLE=%s %v: got error %v, want failure %t\n%s", tc.env, tc.args, err, tc.fail, stderr)
+		}
+		if stdout != tc.stdout {
+			t.Errorf("ZMARSHAL_EXAMPLE=%s %v: got\n%swant\n%s", tc.env, tc.args, stdout, tc.stdout)
+		}
+	}
+}
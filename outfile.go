@@ -0,0 +1,32 @@
+package main
+
+import "os"
+
+// An outFile is the file given by -o.  It is opened, and so created or
+// truncated, only when first written, so a command that fails before
+// writing anything leaves an existing file as it was.
+type outFile struct {
+	path  string
+	flags int
+	f     *os.File
+}
+
+func (o *outFile) Write(b []byte) (int, error) {
+	if o.f == nil {
+		f, err := os.OpenFile(o.path, o.flags, 0666)
+		if err != nil {
+			return 0, err
+		}
+		o.f = f
+	}
+	return o.f.Write(b)
+}
+
+// Close closes the file, first creating it if nothing was written, so a
+// command that succeeds without output still leaves an empty file.
+func (o *outFile) Close() error {
+	if _, err := o.Write(nil); err != nil {
+		return err
+	}
+	return o.f.Close()
+}
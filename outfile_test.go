@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutFile(t *testing.T) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	path := filepath.Join(t.TempDir(), "out")
+	if err := ioutil.WriteFile(path, []byte("old\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	f := &outFile{path: path, flags: flags}
+	if b, _ := ioutil.ReadFile(path); string(b) != "old\n" {
+		t.Errorf("unwritten file changed to %q", b)
+	}
+	if _, err := f.Write([]byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != "new\n" {
+		t.Errorf("written file holds %q", b)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := (&outFile{path: empty, flags: flags}).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(empty); err != nil || len(b) != 0 {
+		t.Errorf("closing an unwritten file: %q, %v", b, err)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "ex4.zson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "out.zson")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-o", path, "-append", "4"}, string(want)},
+		{[]string{"-o", path, "-append", "4"}, string(want) + string(want)},
+		{[]string{"-o", path, "4"}, string(want)},
+	}
+	for _, tc := range tests {
+		if _, stderr, err := runMain(t, tc.args...); err != nil {
+			t.Fatalf("%v: %s\n%s", tc.args, err, stderr)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.want {
+			t.Errorf("%v: file holds\n%swant\n%s", tc.args, b, tc.want)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "ex4.zson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.zson")
+	if err := ioutil.WriteFile(existing, []byte("old\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.zson")
+	tests := []struct {
+		args   []string
+		target string
+	}{
+		{[]string{"-dry-run", "-o", existing, "4"}, existing},
+		{[]string{"-dry-run", "-o", existing, "-append", "4"}, existing},
+		{[]string{"-dry-run", "-o", missing, "4"}, missing},
+		{[]string{"-dry-run", "4"}, "stdout"},
+	}
+	for _, tc := range tests {
+		stdout, stderr, err := runMain(t, tc.args...)
+		if err != nil {
+			t.Fatalf("%v: %s\n%s", tc.args, err, stderr)
+		}
+		if stdout != "" {
+			t.Errorf("%v: wrote %q to stdout", tc.args, stdout)
+		}
+		summary := fmt.Sprintf("dry run: would have written %d bytes in 2 lines to %s\n", len(want), tc.target)
+		if stderr != summary {
+			t.Errorf("%v: stderr is %q, want %q", tc.args, stderr, summary)
+		}
+	}
+	if b, err := ioutil.ReadFile(existing); err != nil || string(b) != "old\n" {
+		t.Errorf("existing file changed to %q, %v", b, err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("missing file was created: %v", err)
+	}
+}
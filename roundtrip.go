@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// roundtrip reads a stream of decorated Things from r and writes each
+// back to w, in order, re-marshaled with the given style.  By default it
+// stops at the first value it cannot decode; with -fail-fast=false it
+// logs and skips such values and reports how many failed at the end.
+// With -type, Things of other types are dropped.  With -only-changed,
+// only values whose compact form changes are written, and the number
+// changed is reported on stderr.  With -limit, it stops reading once it
+// has written that many values.  It also stops, between values, when
+// the -timeout deadline passes or on an interrupt.  Input with no
+// values, including input that is empty or only whitespace, yields no
+// output.  Its own -style flag, as in "roundtrip -style=package",
+// overrides style.
+func roundtrip(w io.Writer, r io.Reader, style zson.TypeStyle, args []string) error {
+	fs := flag.NewFlagSet("roundtrip", flag.ExitOnError)
+	styleFlag := fs.String("style", "", "decoration style ("+styleNames()+")")
+	failFast := fs.Bool("fail-fast", true, "stop at the first value that cannot be decoded")
+	typeName := fs.String("type", "", "keep only Things whose type has this name")
+	timeout := fs.Duration("timeout", 0, "stop after `duration` (0 means no limit)")
+	onlyChanged := fs.Bool("only-changed", false, "write only values whose re-marshaled form differs from the input")
+	limit := fs.Int("limit", 0, "stop after writing `n` values (0 means no limit)")
+	maxBytes := fs.Int64("max-bytes", defaultMaxBytes, "fail if the input is larger than `n` bytes")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		usage()
+	}
+	if *maxBytes < 0 {
+		return fmt.Errorf("roundtrip: -max-bytes must not be negative: %d", *maxBytes)
+	}
+	if *styleFlag != "" {
+		var err error
+		if style, err = parseStyle(*styleFlag); err != nil {
+			return err
+		}
+	}
+	keep, err := typeFilter(*typeName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	logBindings()
+	cr := &countingReader{r: limitReader(r, *maxBytes)}
+	dec := things.NewDecoderWith(cr, newUnmarshaler())
+	enc := things.NewEncoderWith(w, marshalerFunc(style))
+	var failed, written int
+	for k := 0; ; k++ {
+		if err := ctx.Err(); err == context.DeadlineExceeded {
+			return fmt.Errorf("roundtrip: timed out after %s with %d values written: %w", *timeout, written, err)
+		} else if err != nil {
+			return fmt.Errorf("roundtrip: interrupted with %d values written: %w", written, err)
+		}
+		if *limit > 0 && written == *limit {
+			fmt.Fprintf(info, "roundtrip: stopped at the limit of %d values\n", *limit)
+			break
+		}
+		thing, err := dec.Decode()
+		if err == io.EOF {
+			verbosef("read %d bytes\n", cr.n)
+			break
+		}
+		if err == nil {
+			verbosef("value %d: decoded %T\n", k, thing)
+			if !keep(thing) {
+				continue
+			}
+			if *onlyChanged {
+				// Compare the compact form, which is how the
+				// decoder formats the value it read.
+				if s, err := things.MarshalThing(newMarshalerIndent(style, 0), thing); err == nil && s == dec.Value() {
+					continue
+				}
+			}
+			if err = enc.Encode(thing); err == nil {
+				written++
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("value %d %s: %w", k, dec.Position(), err)
+			if *failFast {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, err)
+			failed++
+			if dec.Err() != nil {
+				break
+			}
+		}
+	}
+	if *onlyChanged {
+		// Only changed values are written.
+		fmt.Fprintf(info, "%d values changed\n", written)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d values failed", failed)
+	}
+	return nil
+}
+
+// A countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// stream returns the named Things marshaled with style, one per line.
+func stream(t *testing.T, style zson.TypeStyle, names ...string) string {
+	t.Helper()
+	var b strings.Builder
+	for _, name := range names {
+		s, err := things.ThingToZSON(things.MustMake(name), style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString(s + "\n")
+	}
+	return b.String()
+}
+
+func TestRoundtripOnlyChanged(t *testing.T) {
+	info = &bytes.Buffer{}
+	in := stream(t, zson.StyleSimple, "rose") +
+		stream(t, zson.StylePackage, "flamingo", "quartz") +
+		stream(t, zson.StyleSimple, "ivy")
+	var out bytes.Buffer
+	if err := roundtrip(&out, strings.NewReader(in), zson.StylePackage, []string{"-only-changed"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := stream(t, zson.StylePackage, "rose", "ivy"); out.String() != want {
+		t.Errorf("got\n%swant\n%s", out.String(), want)
+	}
+}
+
+func TestRoundtripStyle(t *testing.T) {
+	info = &bytes.Buffer{}
+	in := stream(t, zson.StyleSimple, "rose", "flamingo", "quartz")
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		var out bytes.Buffer
+		if err := roundtrip(&out, strings.NewReader(in), zson.StyleSimple, []string{"-style", s.Name}); err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if want := stream(t, s.Style, "rose", "flamingo", "quartz"); out.String() != want {
+			t.Errorf("%s: got\n%swant\n%s", s.Name, out.String(), want)
+		}
+	}
+	var out bytes.Buffer
+	if err := roundtrip(&out, strings.NewReader(in), zson.StyleSimple, []string{"-style", "bogus"}); err == nil {
+		t.Error("roundtrip accepted -style=bogus")
+	}
+}
+
+func TestRoundtripErrorPosition(t *testing.T) {
+	info = &bytes.Buffer{}
+	quartz := `{MyColor:"white",MyName:"quartz",Hardness:7}(=Mineral)` + "\n"
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"syntax error", quartz + quartz + "{MyColor:,}\n" + quartz, "value 2 at line 3 (offset 110)"},
+		{"unbound type", quartz + `{a:1}(=Nonesuch)` + "\n" + quartz, "value 1 at line 2 (offset 55)"},
+		{"truncated", quartz + "\n" + `{MyColor:"white"`, "value 1 at line 3 (offset 56)"},
+	}
+	for _, tc := range tests {
+		err := roundtrip(&bytes.Buffer{}, strings.NewReader(tc.input), zson.StyleSimple, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want+":") {
+			t.Errorf("%s: got %v, want an error at %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+// A slowReader returns one line of its text per Read after a delay.
+type slowReader struct {
+	lines []string
+	delay time.Duration
+}
+
+func (r *slowReader) Read(b []byte) (int, error) {
+	time.Sleep(r.delay)
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.lines[0])
+	r.lines[0] = r.lines[0][n:]
+	if r.lines[0] == "" {
+		r.lines = r.lines[1:]
+	}
+	return n, nil
+}
+
+func TestRoundtripTimeout(t *testing.T) {
+	info = &bytes.Buffer{}
+	var lines []string
+	for k := 0; k < 1000; k++ {
+		lines = append(lines, stream(t, zson.StyleSimple, "rose"))
+	}
+	r := &slowReader{lines: lines, delay: time.Millisecond}
+	var out bytes.Buffer
+	err := roundtrip(&out, r, zson.StyleSimple, []string{"-timeout", "50ms"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a deadline error", err)
+	}
+	written := strings.Count(out.String(), "\n")
+	if want := fmt.Sprintf("roundtrip: timed out after 50ms with %d values written", written); !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got %q, want it to begin %q", err, want)
+	}
+	if written == 0 || written == len(lines) {
+		t.Errorf("wrote %d of %d values before the deadline", written, len(lines))
+	}
+}
+
+func TestRoundtripEmpty(t *testing.T) {
+	info = &bytes.Buffer{}
+	for _, in := range []string{"", " ", "\n", " \t\r\n\n", "// only a comment\n"} {
+		var out bytes.Buffer
+		if err := roundtrip(&out, strings.NewReader(in), zson.StyleSimple, nil); err != nil {
+			t.Errorf("%q: %s", in, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("%q: wrote %q", in, out.String())
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	names := []string{"rose", "flamingo", "ivy", "quartz", "emerald"}
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, stream(t, zson.StyleSimple, name))
+	}
+	commands := []struct {
+		name string
+		run  func(w io.Writer, r io.Reader, args []string) error
+		want string
+	}{
+		{"roundtrip", func(w io.Writer, r io.Reader, args []string) error {
+			return roundtrip(w, r, zson.StyleSimple, args)
+		}, stream(t, zson.StyleSimple, "rose", "flamingo")},
+		{"decode", decode, "Plant(red) named \"rose\"\nAnimal(pink) named \"flamingo\"\n"},
+	}
+	for _, cmd := range commands {
+		var stderr bytes.Buffer
+		info = &stderr
+		// A slowReader gives up one value per read, so the values
+		// after the limit are left unread.
+		r := &slowReader{lines: append([]string(nil), lines...)}
+		var out bytes.Buffer
+		if err := cmd.run(&out, r, []string{"-limit", "2"}); err != nil {
+			t.Fatalf("%s: %s", cmd.name, err)
+		}
+		if out.String() != cmd.want {
+			t.Errorf("%s: got\n%swant\n%s", cmd.name, out.String(), cmd.want)
+		}
+		if want := cmd.name + ": stopped at the limit of 2 values\n"; stderr.String() != want {
+			t.Errorf("%s: stderr is %q, want %q", cmd.name, stderr.String(), want)
+		}
+		if len(r.lines) == 0 {
+			t.Errorf("%s: read the whole stream", cmd.name)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// sample writes the marshaled form of the named Thing to w as a template
+// for hand-written input and describes its fields on stderr.  Its own
+// -style flag may follow the name, as in "sample rose -style=package",
+// and overrides style.
+func sample(w io.Writer, style zson.TypeStyle, args []string) error {
+	fs := flag.NewFlagSet("sample", flag.ExitOnError)
+	styleFlag := fs.String("style", "", "decoration style ("+styleNames()+")")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		usage()
+	}
+	name := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		usage()
+	}
+	if *styleFlag != "" {
+		var err error
+		if style, err = parseStyle(*styleFlag); err != nil {
+			return err
+		}
+	}
+	thing, err := things.Make(name)
+	if err != nil {
+		return err
+	}
+	if err := fields(info, name); err != nil {
+		return err
+	}
+	return output(w, style, thing)
+}
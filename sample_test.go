@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestSample(t *testing.T) {
+	defer func() { info = &bytes.Buffer{} }()
+	tests := []struct {
+		args  []string
+		name  string
+		style zson.TypeStyle
+	}{
+		{[]string{"rose"}, "rose", zson.StyleSimple},
+		{[]string{"rose", "-style=package"}, "rose", zson.StylePackage},
+		{[]string{"-style=none", "quartz"}, "quartz", zson.StyleNone},
+	}
+	for _, tc := range tests {
+		var stderr bytes.Buffer
+		info = &stderr
+		var b bytes.Buffer
+		if err := sample(&b, zson.StyleSimple, tc.args); err != nil {
+			t.Fatalf("%v: %s", tc.args, err)
+		}
+		want, err := marshal(tc.style, things.MustMake(tc.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != want+"\n" {
+			t.Errorf("%v: got %q, want %q", tc.args, b.String(), want+"\n")
+		}
+		var fieldList bytes.Buffer
+		if err := fields(&fieldList, tc.name); err != nil {
+			t.Fatal(err)
+		}
+		if stderr.String() != fieldList.String() {
+			t.Errorf("%v: stderr holds\n%swant\n%s", tc.args, stderr.String(), fieldList.String())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// prettySortKeys orders the fields of pretty-printed records by name;
+// see the -pretty-sort-keys flag.
+var prettySortKeys bool
+
+// A sortingWriter sorts the fields of the pretty-printed values written
+// to it.  It relies on things.WriteValue writing each value with a
+// single call to Write, and it leaves text that is not an indented
+// record unchanged.
+type sortingWriter struct {
+	w io.Writer
+}
+
+func (s *sortingWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(s.w, sortKeys(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// sortKeys sorts the fields of every record in the pretty-printed value
+// s, in which each field begins on its own line and is indented one
+// level deeper than the braces of its record.
+func sortKeys(s string) string {
+	return strings.Join(sortLines(strings.Split(s, "\n")), "\n")
+}
+
+func sortLines(lines []string) []string {
+	var out []string
+	for k := 0; k < len(lines); k++ {
+		line := lines[k]
+		out = append(out, line)
+		if !strings.HasSuffix(line, "{") && !strings.HasSuffix(line, "[") {
+			continue
+		}
+		end := k + 1
+		for end < len(lines) && indentOf(lines[end]) > indentOf(line) {
+			end++
+		}
+		body := sortLines(lines[k+1 : end])
+		if strings.HasSuffix(line, "{") {
+			body = sortFields(body)
+		}
+		out = append(out, body...)
+		k = end - 1
+	}
+	return out
+}
+
+// sortFields sorts the fields in the body of a record by name and moves
+// the separating commas to match.
+func sortFields(lines []string) []string {
+	if len(lines) == 0 {
+		return lines
+	}
+	var fields [][]string
+	for _, line := range lines {
+		if len(fields) == 0 || indentOf(line) == indentOf(lines[0]) && !isClose(line) {
+			fields = append(fields, []string{line})
+		} else {
+			fields[len(fields)-1] = append(fields[len(fields)-1], line)
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fieldKey(fields[i][0]) < fieldKey(fields[j][0])
+	})
+	out := make([]string, 0, len(lines))
+	for k, field := range fields {
+		last := strings.TrimSuffix(field[len(field)-1], ",")
+		if k < len(fields)-1 {
+			last += ","
+		}
+		out = append(out, field[:len(field)-1]...)
+		out = append(out, last)
+	}
+	return out
+}
+
+// isClose reports whether line ends a record or list begun on an earlier
+// line.
+func isClose(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]")
+}
+
+func fieldKey(line string) string {
+	line = strings.TrimSpace(line)
+	if k := strings.Index(line, ":"); k >= 0 {
+		return line[:k]
+	}
+	return line
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
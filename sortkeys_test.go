@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// TestSortKeys pins the pretty form of a Mineral with and without
+// -pretty-sort-keys, which moves Hardness before MyColor.
+func TestSortKeys(t *testing.T) {
+	defer func() { pretty = false }()
+	pretty = true
+	tests := []struct {
+		sort   bool
+		golden string
+	}{
+		{false, "quartz-pretty.zson"},
+		{true, "quartz-sorted.zson"},
+	}
+	for _, tc := range tests {
+		got := capture(t, func() error {
+			if tc.sort {
+				out = &sortingWriter{w: out}
+			}
+			s, err := marshal(zson.StyleSimple, things.MustMake("quartz"))
+			if err != nil {
+				return err
+			}
+			return emit(s)
+		})
+		checkGolden(t, tc.golden, got)
+	}
+}
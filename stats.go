@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+// stats writes the size of each named Thing's ZSON under each style and
+// its overhead relative to the smallest.  With no names, it reports on
+// every registered Thing.
+func stats(w io.Writer, names []string) error {
+	if len(names) == 0 {
+		names = things.Names()
+	}
+	styles := things.Styles()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "thing\tstyle\tbytes\t+smallest\t")
+	for _, name := range names {
+		thing, err := things.Make(name)
+		if err != nil {
+			return err
+		}
+		sizes := make([]int, len(styles))
+		smallest := -1
+		for k, s := range styles {
+			out, err := marshal(s.Style, thing)
+			if err != nil {
+				return err
+			}
+			sizes[k] = len(out)
+			if smallest < 0 || sizes[k] < smallest {
+				smallest = sizes[k]
+			}
+		}
+		for k, s := range styles {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%+d\t\n", name, s.Name, sizes[k], sizes[k]-smallest)
+		}
+	}
+	return tw.Flush()
+}
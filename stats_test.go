@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestStats(t *testing.T) {
+	var b bytes.Buffer
+	if err := stats(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	// sizes maps each Thing to its size in bytes under each style.
+	sizes := make(map[string]map[string]int)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			t.Fatalf("malformed line %q", line)
+		}
+		n, err := strconv.Atoi(fields[2])
+		if err != nil {
+			t.Fatalf("line %q: %s", line, err)
+		}
+		if sizes[fields[0]] == nil {
+			sizes[fields[0]] = make(map[string]int)
+		}
+		sizes[fields[0]][fields[1]] = n
+	}
+	for _, name := range things.Names() {
+		size := sizes[name]
+		if len(size) != len(things.Styles()) {
+			t.Errorf("%s: got sizes for %d styles, want %d", name, len(size), len(things.Styles()))
+			continue
+		}
+		if size["package"] < size["simple"] || size["simple"] < size["none"] {
+			t.Errorf("%s: package, simple, and none sizes %d, %d, and %d are not in decreasing order", name, size["package"], size["simple"], size["none"])
+		}
+	}
+	if err := stats(&bytes.Buffer{}, []string{"unicorn"}); err == nil {
+		t.Error("unicorn: no error")
+	}
+}
@@ -0,0 +1,39 @@
+package things
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// MarshalBare marshals t without decorators, which is the smallest
+// encoding but leaves the reader to supply the type; see UnmarshalBare.
+func MarshalBare(t Thing) (string, error) {
+	return ThingToZSON(t, zson.StyleNone)
+}
+
+// UnmarshalBare unmarshals the undecorated value s as a Thing of the
+// concrete type of proto, e.g., Plant{} or &Plant{}, which need not be
+// bound.  The result is a pointer to a new value of that type.  It is an
+// error for s to have a field the type does not, as when proto is the
+// wrong type for s.
+func UnmarshalBare(s string, proto Thing) (Thing, error) {
+	typ := baseType(proto)
+	if typ == nil {
+		return nil, errors.New("UnmarshalBare called with a nil prototype")
+	}
+	if err := CheckFields(s, proto); err != nil {
+		return nil, err
+	}
+	v := reflect.New(typ)
+	if err := zson.Unmarshal(s, v.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	thing, ok := v.Interface().(Thing)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a Thing", v.Type())
+	}
+	return thing, nil
+}
@@ -0,0 +1,49 @@
+package things
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBare(t *testing.T) {
+	tests := []struct {
+		thing Thing
+		proto Thing
+		err   string
+	}{
+		{MustMake("rose"), &Plant{}, ""},
+		{MustMake("flamingo"), &Animal{}, ""},
+		{MustMake("quartz"), &Mineral{}, ""},
+		{MustMake("emerald"), Gem{}, ""},
+		{MustMake("sapphire"), &Gem{}, ""},
+		{MustMake("rose"), &Mineral{}, `unexpected field "BaseThing"`},
+		{MustMake("rose"), nil, "nil prototype"},
+	}
+	for _, tc := range tests {
+		s, err := MarshalBare(tc.thing)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.thing.Name(), err)
+		}
+		if strings.Contains(s, "(=") {
+			t.Errorf("%s: bare value %s is decorated", tc.thing.Name(), s)
+		}
+		got, err := UnmarshalBare(s, tc.proto)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s as %T: got %v, want an error containing %q", tc.thing.Name(), tc.proto, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s as %T: %s", tc.thing.Name(), tc.proto, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(tc.thing) || Describe(got) != Describe(tc.thing) {
+			t.Errorf("%s as %T: got %T %s, want %T %s", tc.thing.Name(), tc.proto, got, Describe(got), tc.thing, Describe(tc.thing))
+		}
+	}
+	if _, err := UnmarshalBare("{a:", &Plant{}); !errors.Is(err, ErrBadInput) {
+		t.Errorf("bad input: got %v, want a bad input error", err)
+	}
+}
@@ -0,0 +1,28 @@
+package things
+
+import (
+	"reflect"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// Clone returns a deep copy of t made by marshaling it to ZSON and
+// unmarshaling the result with every registered type bound.  The clone
+// has the same concrete type as t, including whether it is a pointer.
+func Clone(t Thing) (Thing, error) {
+	if t == nil {
+		return nil, nil
+	}
+	s, err := ThingToZSON(t, zson.StyleSimple)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := ZSONToThing(s)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.TypeOf(t).Kind() != reflect.Ptr {
+		clone = reflect.ValueOf(clone).Elem().Interface().(Thing)
+	}
+	return clone, nil
+}
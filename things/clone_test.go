@@ -0,0 +1,55 @@
+package things
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	tests := []struct {
+		name  string
+		thing Thing
+	}{
+		{"Plant", MustMake("rose")},
+		{"Animal", MustMake("flamingo")},
+		{"Mineral", MustMake("quartz")},
+		{"Gem pointer", MustMake("emerald")},
+		{"Gem value", Gem{"blue", "sapphire"}},
+		{"Garden", &Garden{MyName: "bed", Contents: List{MustMake("rose")}}},
+		{"nil", nil},
+	}
+	for _, tc := range tests {
+		clone, err := Clone(tc.thing)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if reflect.TypeOf(clone) != reflect.TypeOf(tc.thing) {
+			t.Errorf("%s: clone is a %T, want a %T", tc.name, clone, tc.thing)
+			continue
+		}
+		if !reflect.DeepEqual(clone, tc.thing) {
+			t.Errorf("%s: clone %s differs from %s", tc.name, Describe(clone), Describe(tc.thing))
+		}
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	rose := MustMake("rose").(*Plant)
+	clone, err := Clone(rose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rose.setColor("white")
+	if c := clone.Color(); c != "red" {
+		t.Errorf("clone's color changed to %q with the original's", c)
+	}
+	garden := &Garden{MyName: "bed", Contents: List{MustMake("ivy")}}
+	clone, err = Clone(garden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	garden.Contents[0] = MustMake("rose")
+	if c := clone.(*Garden).Contents[0].Color(); c != "green" {
+		t.Errorf("clone's element changed to %q with the original's", c)
+	}
+}
@@ -0,0 +1,58 @@
+package things
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// Marshal returns the StyleSimple ZSON encoding of t.  Together with
+// MarshalIndent, it mirrors the signatures of encoding/json.
+func Marshal(t Thing) ([]byte, error) {
+	s, err := ThingToZSON(t, zson.StyleSimple)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// MarshalIndent is like Marshal but formats the value over multiple
+// lines as encoding/json's MarshalIndent does: each line after the first
+// begins with prefix followed by one copy of indent per level of nesting.
+// Like Marshal, it marshals the state of a StateMarshaler.
+func MarshalIndent(t Thing, prefix, indent string) ([]byte, error) {
+	// Marshal with one space per level and then replace those spaces,
+	// which is safe since ZSON strings never span lines.
+	m := zson.NewMarshalerIndent(1)
+	m.Decorate(zson.StyleSimple)
+	s, err := MarshalThing(m, t)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for k, line := range lines {
+		body := strings.TrimLeft(line, " ")
+		line = strings.Repeat(indent, len(line)-len(body)) + body
+		if k > 0 {
+			line = prefix + line
+		}
+		lines[k] = line
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// Unmarshal decodes the decorated ZSON value in data into the Thing
+// pointed to by v, with every registered type bound, mirroring
+// encoding/json's Unmarshal.
+func Unmarshal(data []byte, v *Thing) error {
+	if v == nil {
+		return errors.New("things: Unmarshal called with a nil *Thing")
+	}
+	thing, err := ZSONToThing(string(data))
+	if err != nil {
+		return err
+	}
+	*v = thing
+	return nil
+}
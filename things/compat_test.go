@@ -0,0 +1,99 @@
+package things
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarshalIndent(t *testing.T) {
+	rose := MustMake("rose")
+	compact, err := Marshal(rose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{BaseThing:{color:"red"}(=BaseThing),MyName:"rose"}(=Plant)`; string(compact) != want {
+		t.Errorf("Marshal: got %s, want %s", compact, want)
+	}
+	tests := []struct {
+		prefix, indent string
+		want           string
+	}{
+		{"", "  ", "{\n  BaseThing: {\n    color: \"red\"\n  } (=BaseThing),\n  MyName: \"rose\"\n} (=Plant)"},
+		{">", "\t", "{\n>\tBaseThing: {\n>\t\tcolor: \"red\"\n>\t} (=BaseThing),\n>\tMyName: \"rose\"\n>} (=Plant)"},
+	}
+	for _, tc := range tests {
+		indented, err := MarshalIndent(rose, tc.prefix, tc.indent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(indented) != tc.want {
+			t.Errorf("prefix %q, indent %q: got %q, want %q", tc.prefix, tc.indent, indented, tc.want)
+		}
+		if !strings.Contains(string(indented), "\n") {
+			t.Errorf("prefix %q, indent %q: output is on one line", tc.prefix, tc.indent)
+		}
+	}
+	// Without a prefix, which ZSON cannot parse, the indented form
+	// decodes to the same Thing as the compact one.
+	indented, err := MarshalIndent(rose, "", "    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]byte{compact, indented} {
+		var thing Thing
+		if err := Unmarshal(data, &thing); err != nil {
+			t.Fatalf("%s: %s", data, err)
+		}
+		if Describe(thing) != Describe(rose) {
+			t.Errorf("%s: unmarshaled %s", data, Describe(thing))
+		}
+	}
+}
+
+func TestMarshalIndentState(t *testing.T) {
+	// A Lamp is marshaled as its LampState by both, so the two differ
+	// only in whitespace.
+	lamp := NewLamp("amber", "desk")
+	compact, err := Marshal(lamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indented, err := MarshalIndent(lamp, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{Color:"amber",Name:"desk"}(=LampState)`; string(compact) != want {
+		t.Errorf("Marshal: got %s, want %s", compact, want)
+	}
+	if got := strings.Join(strings.Fields(string(indented)), ""); got != string(compact) {
+		t.Errorf("MarshalIndent without whitespace is %s, want %s", got, compact)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	for _, name := range Names() {
+		want := MustMake(name)
+		data, err := Marshal(want)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		var got Thing
+		if err := Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if Describe(got) != Describe(want) {
+			t.Errorf("%s: unmarshaled %s, want %s", name, Describe(got), Describe(want))
+		}
+	}
+	data, err := Marshal(MustMake("rose"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Unmarshal(data, nil); err == nil || !strings.Contains(err.Error(), "nil *Thing") {
+		t.Errorf("nil destination: got %v, want a nil *Thing error", err)
+	}
+	var thing Thing
+	if err := Unmarshal([]byte("{a:"), &thing); err == nil || thing != nil {
+		t.Errorf("bad input: got %v and %v, want an error and no Thing", err, thing)
+	}
+}
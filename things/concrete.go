@@ -0,0 +1,31 @@
+package things
+
+import "fmt"
+
+// UnmarshalAs unmarshals a decorated Thing from s with every registered
+// type bound and returns it as a T.  It is an error if s holds some other
+// kind of Thing.
+func UnmarshalAs[T Thing](s string) (T, error) {
+	var zero T
+	t, err := ZSONToThing(s)
+	if err != nil {
+		return zero, err
+	}
+	v, ok := t.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: decoded %T, wanted %T", ErrBadInput, t, zero)
+	}
+	return v, nil
+}
+
+// UnmarshalPlant unmarshals a decorated Plant from s.  It is an error if
+// s holds some other kind of Thing.
+func UnmarshalPlant(s string) (*Plant, error) {
+	return UnmarshalAs[*Plant](s)
+}
+
+// UnmarshalAnimal unmarshals a decorated Animal from s.  It is an error
+// if s holds some other kind of Thing.
+func UnmarshalAnimal(s string) (*Animal, error) {
+	return UnmarshalAs[*Animal](s)
+}
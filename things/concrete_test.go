@@ -0,0 +1,107 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// zsonOf returns the simple-style ZSON of the named Thing.
+func zsonOf(t *testing.T, name string) string {
+	t.Helper()
+	s, err := ThingToZSON(MustMake(name), zson.StyleSimple)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestUnmarshalConcrete(t *testing.T) {
+	tests := []struct {
+		thing string
+		as    string
+		err   string
+	}{
+		{"rose", "Plant", ""},
+		{"ivy", "Plant", ""},
+		{"flamingo", "Animal", ""},
+		{"flamingo", "Plant", "decoded *things.Animal, wanted *things.Plant"},
+		{"rose", "Animal", "decoded *things.Plant, wanted *things.Animal"},
+		{"quartz", "Plant", "decoded *things.Mineral, wanted *things.Plant"},
+	}
+	for _, tc := range tests {
+		s := zsonOf(t, tc.thing)
+		var got Thing
+		var err error
+		if tc.as == "Plant" {
+			var p *Plant
+			p, err = UnmarshalPlant(s)
+			if p != nil {
+				got = p
+			}
+		} else {
+			var a *Animal
+			a, err = UnmarshalAnimal(s)
+			if a != nil {
+				got = a
+			}
+		}
+		if tc.err != "" {
+			if !errors.Is(err, ErrBadInput) || !strings.Contains(err.Error(), tc.err) || got != nil {
+				t.Errorf("%s as %s: got %v and %v, want nil and an error containing %q", tc.thing, tc.as, got, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s as %s: %s", tc.thing, tc.as, err)
+		}
+		if Describe(got) != Describe(MustMake(tc.thing)) {
+			t.Errorf("%s as %s: got %s", tc.thing, tc.as, Describe(got))
+		}
+	}
+}
+
+func TestUnmarshalAs(t *testing.T) {
+	tests := []struct {
+		thing string
+		as    func(string) (Thing, error)
+		err   string
+	}{
+		{"quartz", asThing[*Mineral], ""},
+		{"emerald", asThing[*Gem], ""},
+		{"sunrise", asThing[*Event], ""},
+		{"rose", asThing[Thing], ""},
+		{"emerald", asThing[*Mineral], "decoded *things.Gem, wanted *things.Mineral"},
+		{"rose", asThing[*Animal], "decoded *things.Plant, wanted *things.Animal"},
+	}
+	for _, tc := range tests {
+		got, err := tc.as(zsonOf(t, tc.thing))
+		if tc.err != "" {
+			if !errors.Is(err, ErrBadInput) || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: got %v, want an error containing %q", tc.thing, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tc.thing, err)
+		}
+		if Describe(got) != Describe(MustMake(tc.thing)) {
+			t.Errorf("%s: got %s", tc.thing, Describe(got))
+		}
+	}
+	if _, err := UnmarshalAs[*Plant]("junk"); !errors.Is(err, ErrBadInput) {
+		t.Errorf("junk: got %v, want a bad input error", err)
+	}
+}
+
+// asThing is UnmarshalAs[T] returning a Thing, so that instances for
+// different types fit in one table.
+func asThing[T Thing](s string) (Thing, error) {
+	t, err := UnmarshalAs[T](s)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
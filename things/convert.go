@@ -0,0 +1,37 @@
+package things
+
+import "github.com/brimdata/zed/zson"
+
+// ThingToZSON marshals t as ZSON decorated with style, using its state
+// if t is a StateMarshaler.
+func ThingToZSON(t Thing, style zson.TypeStyle) (string, error) {
+	m := zson.NewMarshaler()
+	m.Decorate(style)
+	return MarshalThing(m, t)
+}
+
+// ZSONToThing unmarshals a decorated Thing from s with every registered
+// type bound.
+func ZSONToThing(s string) (Thing, error) {
+	u, err := newUnmarshaler()
+	if err != nil {
+		return nil, err
+	}
+	var thing Thing
+	if err := UnmarshalInto(u, s, &thing); err != nil {
+		return nil, err
+	}
+	return thing, nil
+}
+
+// TypeName returns the type name in the decorator t gets when marshaled
+// with style, e.g., Plant with StyleSimple, or the empty string for
+// StyleNone or if t cannot be marshaled.  It is taken from the
+// marshaled value itself so that it always agrees with the output.
+func TypeName(t Thing, style zson.TypeStyle) string {
+	s, err := ThingToZSON(t, style)
+	if err != nil {
+		return ""
+	}
+	return decoratorName(s)
+}
@@ -0,0 +1,123 @@
+package things
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// A testColor is a random color for TestRoundTripAllStyles, which may
+// be empty, to keep a Thing's own color, and may hold characters that
+// ZSON must escape.  The ZSON parser normalizes strings to Unicode NFC,
+// so the characters are ones that normalization leaves alone.
+type testColor string
+
+func (testColor) Generate(rng *rand.Rand, size int) reflect.Value {
+	chars := []rune("abcxyz RGB-\"\\\t\n\x00é世{}[](=)")
+	b := make([]rune, rng.Intn(size))
+	for k := range b {
+		b[k] = chars[rng.Intn(len(chars))]
+	}
+	return reflect.ValueOf(testColor(b))
+}
+
+func TestRoundTripAllStyles(t *testing.T) {
+	names := Names()
+	// StyleNone is left out since an undecorated value cannot be
+	// unmarshaled into a Thing.
+	styles := []zson.TypeStyle{zson.StyleSimple, zson.StylePackage, zson.StyleFull}
+	roundTrip := func(k uint8, c testColor) bool {
+		name, color := names[int(k)%len(names)], string(c)
+		want, err := MakeColored(name, color)
+		if err != nil {
+			t.Errorf("MakeColored(%q, %q): %s", name, color, err)
+			return false
+		}
+		for _, style := range styles {
+			s, err := ThingToZSON(want, style)
+			if err != nil {
+				t.Errorf("%s colored %q, style %d: %s", name, color, style, err)
+				return false
+			}
+			got, err := ZSONToThing(s)
+			if err != nil {
+				t.Errorf("%s colored %q, style %d: %s\n%s", name, color, style, err, s)
+				return false
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(want) || got.Color() != want.Color() {
+				t.Errorf("%s colored %q, style %d: unmarshaled %T colored %q from %s", name, color, style, got, got.Color(), s)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestThingToZSON(t *testing.T) {
+	tests := []struct {
+		style zson.TypeStyle
+		want  string
+	}{
+		{zson.StyleNone, `{BaseThing:{color:"pink"},MyName:"flamingo"}`},
+		{zson.StyleSimple, `{BaseThing:{color:"pink"}(=BaseThing),MyName:"flamingo"}(=Animal)`},
+		{zson.StylePackage, `{BaseThing:{color:"pink"}(=things.BaseThing),MyName:"flamingo"}(=things.Animal)`},
+		{zson.StyleFull, `{BaseThing:{color:"pink"}(=github.com/mccanne/zmarshal/things.BaseThing),MyName:"flamingo"}(=github.com/mccanne/zmarshal/things.Animal)`},
+	}
+	for _, tc := range tests {
+		got, err := ThingToZSON(MustMake("flamingo"), tc.style)
+		if err != nil {
+			t.Fatalf("style %d: %s", tc.style, err)
+		}
+		if got != tc.want {
+			t.Errorf("style %d: got %s, want %s", tc.style, got, tc.want)
+		}
+		if tc.style == zson.StyleNone {
+			continue
+		}
+		thing, err := ZSONToThing(got)
+		if err != nil {
+			t.Fatalf("style %d: %s", tc.style, err)
+		}
+		if a, ok := thing.(*Animal); !ok || a.Color() != "pink" || a.Name() != "flamingo" {
+			t.Errorf("style %d: unmarshaled %s, want the flamingo", tc.style, Describe(thing))
+		}
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		thing Thing
+		names []string
+	}{
+		{MustMake("rose"), []string{"", "Plant", "things.Plant", "github.com/mccanne/zmarshal/things.Plant"}},
+		{MustMake("quartz"), []string{"", "Mineral", "things.Mineral", "github.com/mccanne/zmarshal/things.Mineral"}},
+		{&Garden{MyName: "bed", Contents: List{MustMake("rose")}}, []string{"", "Garden", "things.Garden", "github.com/mccanne/zmarshal/things.Garden"}},
+		{NewLamp("amber", "desk"), []string{"", "LampState", "things.LampState", "github.com/mccanne/zmarshal/things.LampState"}},
+	}
+	styles := []zson.TypeStyle{zson.StyleNone, zson.StyleSimple, zson.StylePackage, zson.StyleFull}
+	for _, tc := range tests {
+		for k, style := range styles {
+			name := TypeName(tc.thing, style)
+			if name != tc.names[k] {
+				t.Errorf("%s, style %d: got %q, want %q", Describe(tc.thing), style, name, tc.names[k])
+			}
+			if name == "" {
+				continue
+			}
+			s, err := ThingToZSON(tc.thing, style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(s, "(="+name+")") {
+				t.Errorf("%s, style %d: %s is not decorated %s", Describe(tc.thing), style, s, name)
+			}
+		}
+	}
+}
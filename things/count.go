@@ -0,0 +1,14 @@
+package things
+
+// CountByColor returns the number of Things of each color.  Nil Things
+// are not counted.
+func CountByColor(things []Thing) map[string]int {
+	counts := make(map[string]int)
+	for _, thing := range things {
+		if isNil(thing) {
+			continue
+		}
+		counts[thing.Color()]++
+	}
+	return counts
+}
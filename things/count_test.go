@@ -0,0 +1,25 @@
+package things
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountByColor(t *testing.T) {
+	tests := []struct {
+		name   string
+		things []Thing
+		counts map[string]int
+	}{
+		{"none", nil, map[string]int{}},
+		{"one", []Thing{MustMake("rose")}, map[string]int{"red": 1}},
+		{"mixed types", []Thing{MustMake("rose"), MustMake("ivy"), MustMake("rose"), MustMake("flamingo"), MustMake("emerald")},
+			map[string]int{"red": 2, "green": 2, "pink": 1}},
+		{"nil Thing", []Thing{MustMake("rose"), nil, (*Plant)(nil)}, map[string]int{"red": 1}},
+	}
+	for _, tc := range tests {
+		if counts := CountByColor(tc.things); !reflect.DeepEqual(counts, tc.counts) {
+			t.Errorf("%s: got %v, want %v", tc.name, counts, tc.counts)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package custom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// Plant shares its name with things.Plant, so the two collide under
+// StyleSimple but not under StylePackage or StyleFull.
+type Plant struct {
+	Species string
+}
+
+func (p *Plant) Color() string { return "green" }
+func (p *Plant) Name() string  { return p.Species }
+
+func init() {
+	things.Register("fern", func() things.Thing { return &Plant{"fern"} })
+}
+
+func TestAmbiguousType(t *testing.T) {
+	u := zson.NewUnmarshaler()
+	if err := things.BindAll(u); err != nil {
+		t.Fatalf("BindAll: %s", err)
+	}
+	tests := []struct {
+		style     zson.TypeStyle
+		ambiguous bool
+	}{
+		{zson.StyleSimple, true},
+		{zson.StylePackage, false},
+		{zson.StyleFull, false},
+	}
+	for _, tc := range tests {
+		for _, name := range []string{"rose", "fern"} {
+			want := things.MustMake(name)
+			s, err := things.ThingToZSON(want, tc.style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got things.Thing
+			err = things.UnmarshalInto(u, s, &got)
+			if tc.ambiguous {
+				if !errors.Is(err, things.ErrAmbiguousType) {
+					t.Errorf("%s: got %v, want an ambiguous type error", s, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s: %s", s, err)
+				continue
+			}
+			if equal, err := things.Equal(want, got); err != nil || !equal {
+				t.Errorf("%s: unmarshaled %s", s, things.Describe(got))
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package custom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// Fungus is a Thing defined outside package things, registered the way
+// another module would register its own types.
+type Fungus struct {
+	Cap    string
+	Edible bool
+}
+
+func (f *Fungus) Color() string { return f.Cap }
+func (f *Fungus) Name() string  { return "morel" }
+
+func init() {
+	things.Register("morel", func() things.Thing { return &Fungus{"brown", true} })
+}
+
+func TestRegisterCustom(t *testing.T) {
+	morel, err := things.Make("morel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	flamingo := things.MustMake("flamingo")
+	u := zson.NewUnmarshaler()
+	if err := things.BindAll(u); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		zs, err := things.ThingToZSON(morel, s.Style)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		got, err := things.ZSONToThing(zs)
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", s.Name, err, zs)
+		}
+		if f, ok := got.(*Fungus); !ok || *f != (Fungus{"brown", true}) {
+			t.Errorf("%s: unmarshaled %#v from %s", s.Name, got, zs)
+		}
+
+		m := zson.NewMarshaler()
+		m.Decorate(s.Style)
+		zs, err = things.MarshalThings(m, []things.Thing{flamingo, morel})
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		list, err := things.UnmarshalThings(u, zs)
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", s.Name, err, zs)
+		}
+		if len(list) != 2 {
+			t.Fatalf("%s: unmarshaled %d Things, want 2", s.Name, len(list))
+		}
+		if _, ok := list[1].(*Fungus); !ok {
+			t.Errorf("%s: second element is a %T, want a *Fungus", s.Name, list[1])
+		}
+
+		var b bytes.Buffer
+		enc := things.NewEncoder(&b, s.Style)
+		for _, thing := range []things.Thing{morel, flamingo, morel} {
+			if err := enc.Encode(thing); err != nil {
+				t.Fatal(err)
+			}
+		}
+		decoded, err := things.DecodeAll(&b)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if len(decoded) != 3 {
+			t.Fatalf("%s: decoded %d Things, want 3", s.Name, len(decoded))
+		}
+		for k, thing := range decoded {
+			if want := []string{"morel", "flamingo", "morel"}[k]; thing.Name() != want {
+				t.Errorf("%s: value %d is %s, want %s", s.Name, k, things.Describe(thing), want)
+			}
+		}
+	}
+}
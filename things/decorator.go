@@ -0,0 +1,30 @@
+package things
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// A Decorator computes the ZSON type name for a registered Thing type.
+// zson's marshaler only offers its fixed TypeStyles, so a Decorator is
+// applied by turning it into named bindings with DecoratorBindings.
+type Decorator func(reflect.Type) string
+
+// Lowercase is a Decorator that names each type by its lowercased simple
+// name, e.g., plant.
+func Lowercase(typ reflect.Type) string {
+	return strings.ToLower(typ.Name())
+}
+
+// DecoratorBindings returns a named binding for each registered type
+// using the name computed by d.  The same bindings must be given to the
+// unmarshaler for the decorated values to be read back.
+func DecoratorBindings(d Decorator) []zson.Binding {
+	var bindings []zson.Binding
+	for _, t := range templates() {
+		bindings = append(bindings, zson.Binding{Name: d(reflect.TypeOf(t)), Template: t})
+	}
+	return bindings
+}
@@ -0,0 +1,46 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestLowercaseDecorator(t *testing.T) {
+	bindings := DecoratorBindings(Lowercase)
+	for _, name := range Names() {
+		thing := MustMake(name)
+		m := zson.NewMarshaler()
+		m.Decorate(zson.StyleSimple)
+		if err := m.NamedBindings(bindings); err != nil {
+			t.Fatal(err)
+		}
+		s, err := m.Marshal(thing)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		want := "(=" + strings.ToLower(TypeName(thing, zson.StyleSimple)) + ")"
+		if !strings.HasSuffix(s, want) {
+			t.Errorf("%s: %s is not decorated %s", name, s, want)
+		}
+		u, err := newUnmarshaler()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Thing
+		if err := UnmarshalInto(u, s, &got); !errors.Is(err, ErrUnboundType) {
+			t.Errorf("%s without the bindings: got %v, want an unbound type error", name, err)
+		}
+		if err := u.NamedBindings(bindings); err != nil {
+			t.Fatal(err)
+		}
+		if err := UnmarshalInto(u, s, &got); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if Describe(got) != Describe(thing) {
+			t.Errorf("%s: unmarshaled %s, want %s", name, Describe(got), Describe(thing))
+		}
+	}
+}
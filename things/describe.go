@@ -0,0 +1,43 @@
+package things
+
+import (
+	"fmt"
+	"time"
+)
+
+// Describe returns a short human-readable summary of t such as
+// Animal(pink) named "flamingo".
+func Describe(t Thing) string {
+	var kind string
+	switch t := t.(type) {
+	case nil:
+		return "nothing"
+	case *Plant:
+		kind = "Plant"
+	case *Animal:
+		kind = "Animal"
+	case *Mineral:
+		return fmt.Sprintf("Mineral(%s, hardness %d) named %q", t.MyColor, t.Hardness, t.MyName)
+	case *Event:
+		return fmt.Sprintf("Event(%s) named %q at %s", t.MyColor, t.MyName, t.Time().Format(time.RFC3339Nano))
+	case *Lamp:
+		kind = "Lamp"
+	case *Machine:
+		if err := t.LastErr.Err(); err != nil {
+			return fmt.Sprintf("Machine(%s) named %q failing with %q", t.MyColor, t.MyName, err)
+		}
+		kind = "Machine"
+	case *Swatch:
+		if t.MyColor == nil {
+			return fmt.Sprintf("Swatch of unknown color named %q", t.MyName)
+		}
+		kind = "Swatch"
+	case *Garden:
+		return fmt.Sprintf("Garden %q of %d things", t.MyName, len(t.Contents))
+	case Gem, *Gem:
+		kind = "Gem"
+	default:
+		kind = fmt.Sprintf("%T", t)
+	}
+	return fmt.Sprintf("%s(%s) named %q", kind, t.Color(), t.Name())
+}
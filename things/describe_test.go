@@ -0,0 +1,52 @@
+package things
+
+import (
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := map[string]string{
+		"diamond":  `Mineral(clear, hardness 10) named "diamond"`,
+		"emerald":  `Gem(green) named "emerald"`,
+		"flamingo": `Animal(pink) named "flamingo"`,
+		"ivy":      `Plant(green) named "ivy"`,
+		"quartz":   `Mineral(white, hardness 7) named "quartz"`,
+		"robot":    `Machine(silver) named "robot" failing with "boom"`,
+		"rose":     `Plant(red) named "rose"`,
+		"sapphire": `Gem(blue) named "sapphire"`,
+		"sunrise":  `Event(orange) named "sunrise" at 2021-06-21T04:43:10.123456789Z`,
+		"swatch":   `Swatch of unknown color named "swatch"`,
+	}
+	for _, name := range Names() {
+		want, ok := tests[name]
+		if !ok {
+			t.Errorf("no description pinned for %s", name)
+			continue
+		}
+		thing := MustMake(name)
+		if got := Describe(thing); got != want {
+			t.Errorf("%s: got %s, want %s", name, got, want)
+		}
+		// The description of an unmarshaled Thing is the same.
+		s, err := ThingToZSON(thing, zson.StyleSimple)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		thing, err = ZSONToThing(s)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if got := Describe(thing); got != want {
+			t.Errorf("%s, unmarshaled: got %s, want %s", name, got, want)
+		}
+	}
+	if got := Describe(nil); got != "nothing" {
+		t.Errorf("nil: got %s, want nothing", got)
+	}
+	garden := &Garden{MyName: "bed", Contents: List{MustMake("rose"), MustMake("ivy")}}
+	if got, want := Describe(garden), `Garden "bed" of 2 things`; got != want {
+		t.Errorf("Garden: got %s, want %s", got, want)
+	}
+}
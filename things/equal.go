@@ -0,0 +1,23 @@
+package things
+
+import "github.com/brimdata/zed/zson"
+
+// Equal reports whether a and b are structurally equal, irrespective of
+// pointer identity, by comparing their ZSON encodings under StylePackage.
+// This is reliable because zson emits struct fields in declaration order,
+// so equal values of the same type always produce the same text, while
+// the package-qualified decorator keeps Things of different types with
+// identical fields from comparing equal.  Each is marshaled with its own
+// marshaler, since one that has already written a type definition only
+// refers to the type by name after that.
+func Equal(a, b Thing) (bool, error) {
+	sa, err := ThingToZSON(a, zson.StylePackage)
+	if err != nil {
+		return false, err
+	}
+	sb, err := ThingToZSON(b, zson.StylePackage)
+	if err != nil {
+		return false, err
+	}
+	return sa == sb, nil
+}
@@ -0,0 +1,30 @@
+package things
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  Thing
+		equal bool
+	}{
+		{"same pointer", MustMake("rose"), nil, true},
+		{"equal Plants", MustMake("rose"), MustMake("rose"), true},
+		{"different colors", MustMake("rose"), MustMake("ivy"), false},
+		{"same fields, different types", &Plant{BaseThing{"pink"}, "flamingo"}, MustMake("flamingo"), false},
+		{"pointer and value", &Gem{"red", "ruby"}, Gem{"red", "ruby"}, true},
+	}
+	for _, tc := range tests {
+		b := tc.b
+		if b == nil {
+			b = tc.a
+		}
+		equal, err := Equal(tc.a, b)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if equal != tc.equal {
+			t.Errorf("%s: Equal is %t, want %t", tc.name, equal, tc.equal)
+		}
+	}
+}
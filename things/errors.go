@@ -0,0 +1,19 @@
+package things
+
+import "errors"
+
+// Errors returned by this package wrap one of these so that callers can
+// tell the kinds of failure apart with errors.Is.
+var (
+	// ErrUnknownThing means a name passed to Make is not registered.
+	ErrUnknownThing = errors.New("unknown thing")
+	// ErrUnboundType means a value is decorated with a type name that
+	// was not bound to the unmarshaler.
+	ErrUnboundType = errors.New("unbound type")
+	// ErrAmbiguousType means a value is decorated with a type name that
+	// more than one registered type answers to.
+	ErrAmbiguousType = errors.New("ambiguous type")
+	// ErrBadInput means ZSON input is malformed or does not fit the
+	// destination.
+	ErrBadInput = errors.New("bad input")
+)
@@ -0,0 +1,58 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestErrorKinds(t *testing.T) {
+	quartz, err := ThingToZSON(MustMake("quartz"), zson.StyleSimple)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentinels := []error{ErrUnknownThing, ErrUnboundType, ErrAmbiguousType, ErrBadInput}
+	tests := []struct {
+		name string
+		fn   func() error
+		want error
+	}{
+		{"Make", func() error {
+			_, err := Make("unicorn")
+			return err
+		}, ErrUnknownThing},
+		{"MakeColored", func() error {
+			_, err := MakeColored("unicorn", "red")
+			return err
+		}, ErrUnknownThing},
+		{"UnmarshalInto", func() error {
+			u := zson.NewUnmarshaler()
+			if err := u.Bind(Plant{}); err != nil {
+				return err
+			}
+			var thing Thing
+			return UnmarshalInto(u, quartz, &thing)
+		}, ErrUnboundType},
+		{"ZSONToThing", func() error {
+			_, err := ZSONToThing("{a:")
+			return err
+		}, ErrBadInput},
+		{"DecodeAll", func() error {
+			_, err := DecodeAll(strings.NewReader(quartz + "\njunk\n"))
+			return err
+		}, ErrBadInput},
+		{"CheckFields", func() error {
+			return CheckFields(`{MyColor:"white",MyWeight:3}`, &Mineral{})
+		}, ErrBadInput},
+	}
+	for _, tc := range tests {
+		err := tc.fn()
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) != (sentinel == tc.want) {
+				t.Errorf("%s: errors.Is(%v, %v) is %t", tc.name, err, sentinel, errors.Is(err, sentinel))
+			}
+		}
+	}
+}
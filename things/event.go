@@ -0,0 +1,40 @@
+package things
+
+import (
+	"time"
+
+	"github.com/brimdata/zed/pkg/nano"
+)
+
+// An Event is a Thing with a timestamp.  zson cannot marshal a time.Time,
+// whose fields are unexported, so When is a nano.Ts, which zson marshals
+// as a Zed time of nanoseconds since the Unix epoch.  An Event thus keeps
+// the full sub-second precision of its time but not its location: Time
+// returns the same instant in UTC.  Times before 1678 or after 2262 do
+// not fit in a nano.Ts.
+type Event struct {
+	MyColor string
+	MyName  string
+	When    nano.Ts
+}
+
+// NewEvent returns an Event that happened at t.
+func NewEvent(color, name string, t time.Time) *Event {
+	return &Event{color, name, nano.TimeToTs(t)}
+}
+
+func (e *Event) Color() string     { return e.MyColor }
+func (e *Event) Name() string      { return e.MyName }
+func (e *Event) setColor(c string) { e.MyColor = c }
+
+// Time returns when e happened, in UTC.
+func (e *Event) Time() time.Time { return e.When.Time() }
+
+// SetTime sets when e happened to t.
+func (e *Event) SetTime(t time.Time) { e.When = nano.TimeToTs(t) }
+
+func init() {
+	Register("sunrise", func() Thing {
+		return NewEvent("orange", "sunrise", time.Date(2021, 6, 21, 4, 43, 10, 123456789, time.UTC))
+	})
+}
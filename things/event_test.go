@@ -0,0 +1,43 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestEventTime(t *testing.T) {
+	tests := []struct {
+		name string
+		when time.Time
+	}{
+		{"nanoseconds", time.Date(2021, 6, 21, 4, 43, 10, 123456789, time.UTC)},
+		{"one nanosecond", time.Date(2021, 6, 21, 4, 43, 10, 1, time.UTC)},
+		{"whole second", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"before the epoch", time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		{"another location", time.Date(2021, 6, 21, 4, 43, 10, 5, time.FixedZone("UTC+5", 5*60*60))},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(NewEvent("orange", "event", tc.when), s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			thing, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			e, ok := thing.(*Event)
+			if !ok {
+				t.Fatalf("%s, %s: unmarshaled a %T", tc.name, s.Name, thing)
+			}
+			if got := e.Time(); !got.Equal(tc.when) || got.Location() != time.UTC {
+				t.Errorf("%s, %s: time is %s, want %s in UTC\n%s", tc.name, s.Name, got.Format(time.RFC3339Nano), tc.when.Format(time.RFC3339Nano), zs)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package things
+
+// A Garden holds Things of any type in a List, so that each of its
+// Contents is marshaled with its own decorator.  A Garden is itself a
+// Thing, so Gardens may be nested and each level keeps its decorator.
+// Nil Contents are marshaled as null, as is a nil Thing within them, and
+// both unmarshal back to nil.
+type Garden struct {
+	MyName   string `zed:"Name"`
+	Contents List
+}
+
+func (g *Garden) Name() string { return g.MyName }
+
+// Color returns the color shared by everything in the Garden, including
+// the contents of nested Gardens, or "" if the Garden is empty or its
+// contents differ in color.
+func (g *Garden) Color() string {
+	var color string
+	for k, thing := range g.Contents {
+		if isNil(thing) {
+			return ""
+		}
+		c := thing.Color()
+		if c == "" || (k > 0 && c != color) {
+			return ""
+		}
+		color = c
+	}
+	return color
+}
+
+// Flatten returns the Things in t that are not Gardens, descending into
+// Gardens depth first and in order, or just t if it is not a Garden.
+// Nil Things are skipped.
+func Flatten(t Thing) []Thing {
+	g, ok := t.(*Garden)
+	if !ok {
+		if isNil(t) {
+			return nil
+		}
+		return []Thing{t}
+	}
+	var out []Thing
+	for _, thing := range g.Contents {
+		out = append(out, Flatten(thing)...)
+	}
+	return out
+}
+
+func init() {
+	bindOnly(Garden{})
+}
@@ -0,0 +1,160 @@
+package things
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestGardenRoundtrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		garden *Garden
+	}{
+		{"nil contents", &Garden{MyName: "empty"}},
+		{"empty contents", &Garden{MyName: "bare", Contents: List{}}},
+		{"mixed types", &Garden{MyName: "backyard", Contents: List{MustMake("rose"), MustMake("flamingo"), MustMake("quartz")}}},
+		{"nil element", &Garden{MyName: "gap", Contents: List{MustMake("rose"), nil, MustMake("ivy")}}},
+		{"nested", &Garden{MyName: "park", Contents: List{
+			&Garden{MyName: "bed", Contents: List{MustMake("rose"), MustMake("flamingo")}},
+			MustMake("ivy"),
+		}}},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(tc.garden, s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			thing, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			g, ok := thing.(*Garden)
+			if !ok {
+				t.Fatalf("%s, %s: unmarshaled a %T", tc.name, s.Name, thing)
+			}
+			if (g.Contents == nil) != (len(tc.garden.Contents) == 0) {
+				t.Errorf("%s, %s: Contents nil is %t, want %t", tc.name, s.Name, g.Contents == nil, len(tc.garden.Contents) == 0)
+			}
+			equal, err := Equal(tc.garden, g)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			if !equal {
+				t.Errorf("%s, %s: round trip changed the Garden\n%s", tc.name, s.Name, zs)
+			}
+			want, got := Flatten(tc.garden), Flatten(g)
+			if len(got) != len(want) {
+				t.Fatalf("%s, %s: flattened to %d Things, want %d", tc.name, s.Name, len(got), len(want))
+			}
+			for k := range want {
+				if TypeName(got[k], s.Style) != TypeName(want[k], s.Style) || got[k].Color() != want[k].Color() {
+					t.Errorf("%s, %s: element %d is %s, want %s", tc.name, s.Name, k, Describe(got[k]), Describe(want[k]))
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyListType(t *testing.T) {
+	m := zson.NewMarshaler()
+	m.Decorate(zson.StyleSimple)
+	s, err := m.Marshal([]List{{}, {MustMake("rose")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := newUnmarshaler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lists []List
+	if err := UnmarshalInto(u, s, &lists); err != nil {
+		t.Fatalf("%s\n%s", err, s)
+	}
+	if len(lists) != 2 || lists[0] != nil || len(lists[1]) != 1 {
+		t.Errorf("unmarshaled %v from %s", lists, s)
+	}
+}
+
+func TestNestedGarden(t *testing.T) {
+	for _, depth := range []int{1, 3, 10} {
+		var thing Thing = MustMake("flamingo")
+		for k := 0; k < depth; k++ {
+			thing = &Garden{MyName: "level", Contents: List{thing, MustMake("ivy")}}
+		}
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(thing, s.Style)
+			if err != nil {
+				t.Fatalf("depth %d, %s: %s", depth, s.Name, err)
+			}
+			got, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("depth %d, %s: %s", depth, s.Name, err)
+			}
+			for k := 0; k < depth; k++ {
+				g, ok := got.(*Garden)
+				if !ok {
+					t.Fatalf("depth %d, %s: level %d is a %T", depth, s.Name, k, got)
+				}
+				got = g.Contents[0]
+			}
+			if a, ok := got.(*Animal); !ok || a.Color() != "pink" {
+				t.Errorf("depth %d, %s: leaf is %s, want a pink Animal", depth, s.Name, Describe(got))
+			}
+		}
+	}
+}
+
+func TestGardenColor(t *testing.T) {
+	tests := []struct {
+		name   string
+		garden *Garden
+		color  string
+	}{
+		{"empty", &Garden{}, ""},
+		{"one color", &Garden{Contents: List{MustMake("ivy"), MustMake("emerald")}}, "green"},
+		{"mixed colors", &Garden{Contents: List{MustMake("rose"), MustMake("ivy")}}, ""},
+		{"nested", &Garden{Contents: List{&Garden{Contents: List{MustMake("ivy")}}, MustMake("emerald")}}, "green"},
+		{"nil Thing", &Garden{Contents: List{MustMake("ivy"), nil}}, ""},
+	}
+	for _, tc := range tests {
+		if color := tc.garden.Color(); color != tc.color {
+			t.Errorf("%s: Color is %q, want %q", tc.name, color, tc.color)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name  string
+		thing Thing
+		want  []string
+	}{
+		{"not a Garden", MustMake("rose"), []string{"rose"}},
+		{"nil", nil, nil},
+		{"empty Garden", &Garden{MyName: "bare"}, nil},
+		{"two levels", &Garden{MyName: "park", Contents: List{
+			MustMake("quartz"),
+			&Garden{MyName: "bed", Contents: List{MustMake("rose"), MustMake("ivy")}},
+			MustMake("flamingo"),
+			&Garden{MyName: "pond", Contents: List{nil, MustMake("emerald")}},
+		}}, []string{"quartz", "rose", "ivy", "flamingo", "emerald"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, thing := range Flatten(tc.thing) {
+			got = append(got, thing.Name())
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
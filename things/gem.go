@@ -0,0 +1,25 @@
+package things
+
+// Gem implements Thing with value receivers, unlike Plant, Animal, and
+// Mineral, which use pointer receivers.  Both kinds are bound to an
+// unmarshaler the same way, with a zero value template such as Gem{} or
+// Plant{}.  When unmarshaling into a Thing, zson allocates a new value
+// and stores a pointer to it in the interface, so a Gem comes back as a
+// *Gem, which satisfies Thing because its method set includes the value
+// methods.  The Gem constructors therefore return pointers too, so that
+// every Thing from Make has the same concrete type as its unmarshaled
+// counterpart and type switches on *T work for both.
+type Gem struct {
+	MyColor string
+	MyName  string
+}
+
+func (g Gem) Color() string { return g.MyColor }
+func (g Gem) Name() string  { return g.MyName }
+
+func (g *Gem) setColor(c string) { g.MyColor = c }
+
+func init() {
+	Register("emerald", func() Thing { return &Gem{"green", "emerald"} })
+	Register("sapphire", func() Thing { return &Gem{"blue", "sapphire"} })
+}
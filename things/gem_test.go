@@ -0,0 +1,40 @@
+package things
+
+import (
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestGemRoundtrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		thing Thing
+	}{
+		{"value", Gem{"blue", "sapphire"}},
+		{"pointer", &Gem{"green", "emerald"}},
+		{"registered", MustMake("sapphire")},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(tc.thing, s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			thing, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			g, ok := thing.(*Gem)
+			if !ok {
+				t.Fatalf("%s, %s: unmarshaled a %T", tc.name, s.Name, thing)
+			}
+			if g.Color() != tc.thing.Color() || g.Name() != tc.thing.Name() {
+				t.Errorf("%s, %s: got %s, want %s", tc.name, s.Name, Describe(g), Describe(tc.thing))
+			}
+		}
+	}
+}
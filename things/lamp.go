@@ -0,0 +1,38 @@
+package things
+
+import "fmt"
+
+// A Lamp keeps its state in unexported fields and implements
+// StateMarshaler to marshal it as a LampState.  It is not registered,
+// since marshaling a Lamp directly rather than through MarshalThing
+// yields an empty record.
+type Lamp struct {
+	color string
+	name  string
+}
+
+// LampState is the marshaled form of a Lamp.
+type LampState struct {
+	Color string
+	Name  string
+}
+
+func NewLamp(color, name string) *Lamp {
+	return &Lamp{color: color, name: name}
+}
+
+func (l *Lamp) Color() string { return l.color }
+func (l *Lamp) Name() string  { return l.name }
+
+func (l *Lamp) MarshalState() interface{} {
+	return LampState{Color: l.color, Name: l.name}
+}
+
+func (l *Lamp) SetState(state interface{}) error {
+	s, ok := state.(LampState)
+	if !ok {
+		return fmt.Errorf("Lamp: cannot set state from %T", state)
+	}
+	l.color, l.name = s.Color, s.Name
+	return nil
+}
@@ -0,0 +1,143 @@
+package things
+
+import (
+	"fmt"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zcode"
+	"github.com/brimdata/zed/zson"
+)
+
+// A List is a slice of Things that marshals each element as a value of
+// its own concrete type, e.g., [{MyColor:"red"}(=Gem),{...}(=Mineral)].
+// zson cannot marshal a []Thing of mixed types itself, since it gives a
+// list the type of its last element (zed issue #2575), so the other
+// elements come out with the wrong decorator or fail to marshal at all.
+// A List is instead given the type its elements share or, if they
+// differ, a union of their types, and it unmarshals each element with
+// the bindings of the unmarshaler in use.
+//
+// A nil element is marshaled as null and unmarshals back to a nil Thing.
+// A nil or empty List is marshaled as null and unmarshals as a nil List.
+// zson formats an empty list of a named type as [](=List), without its
+// element type, which the parser then takes to be null, so an empty List
+// could not be read back in a value with other Lists.
+type List []Thing
+
+func (l List) MarshalZNG(m *zson.MarshalZNGContext) (zed.Type, error) {
+	if len(l) == 0 {
+		m.Builder.Append(nil)
+		return m.LookupTypeArray(zed.TypeNull), nil
+	}
+	return marshalArray(m, len(l), func(k int) interface{} {
+		if isNil(l[k]) {
+			return nil
+		}
+		return l[k]
+	})
+}
+
+func (l *List) UnmarshalZNG(u *zson.UnmarshalZNGContext, zv *zed.Value) error {
+	if zv.Bytes == nil {
+		*l = nil
+		return nil
+	}
+	var list List
+	err := unmarshalArray(zv, func(k int, elem *zed.Value) error {
+		var thing Thing
+		if elem != nil {
+			if err := unmarshalValue(u, elem, &thing); err != nil {
+				return fmt.Errorf("element %d: %w", k, err)
+			}
+		}
+		list = append(list, thing)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// marshalArray marshals the n values returned by elem as an array and
+// returns its type.  Each value is marshaled as its own concrete type,
+// and the array is given the type they share or, if they differ, the
+// union of their types.  A nil value is marshaled as null.
+func marshalArray(m *zson.MarshalZNGContext, n int, elem func(k int) interface{}) (zed.Type, error) {
+	// Each value is marshaled with a builder of its own so that its
+	// body can be placed after a union selector once the types of all
+	// the values are known.
+	outer := m.Builder
+	defer func() { m.Builder = outer }()
+	types := make([]zed.Type, n)
+	bodies := make([]zcode.Bytes, n)
+	var distinct []zed.Type
+	seen := make(map[zed.Type]bool)
+	for k := 0; k < n; k++ {
+		v := elem(k)
+		if v == nil {
+			continue
+		}
+		m.Builder = zcode.Builder{}
+		typ, err := m.MarshalValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", k, err)
+		}
+		it := m.Builder.Bytes().Iter()
+		types[k], bodies[k] = typ, it.Next()
+		if !seen[typ] {
+			seen[typ] = true
+			distinct = append(distinct, typ)
+		}
+	}
+	var union *zed.TypeUnion
+	var inner zed.Type
+	switch len(distinct) {
+	case 0:
+		inner = zed.TypeNull
+	case 1:
+		inner = distinct[0]
+	default:
+		union = m.LookupTypeUnion(distinct)
+		inner = union
+	}
+	outer.BeginContainer()
+	for k, body := range bodies {
+		if union != nil && types[k] != nil {
+			zed.BuildUnion(&outer, union.Selector(types[k]), body)
+		} else {
+			outer.Append(body)
+		}
+	}
+	outer.EndContainer()
+	return m.LookupTypeArray(inner), nil
+}
+
+// unmarshalArray calls elem with each element of the array zv as a value
+// of its own concrete type, taken from the union if the array has one,
+// or with nil for a null element.
+func unmarshalArray(zv *zed.Value, elem func(k int, val *zed.Value) error) error {
+	arr, ok := zed.TypeUnder(zv.Type).(*zed.TypeArray)
+	if !ok {
+		return fmt.Errorf("%w: %s is not an array", ErrBadInput, zson.FormatType(zv.Type))
+	}
+	union, _ := zed.TypeUnder(arr.Type).(*zed.TypeUnion)
+	k := 0
+	for it := zv.Bytes.Iter(); !it.Done(); k++ {
+		typ, body := arr.Type, it.Next()
+		if body == nil {
+			if err := elem(k, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if union != nil {
+			typ, body = union.SplitZNG(body)
+		}
+		if err := elem(k, zed.NewValue(typ, body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
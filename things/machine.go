@@ -0,0 +1,44 @@
+package things
+
+import "errors"
+
+// An Error holds a Go error so that it can be marshaled, which an
+// error-typed field cannot be since its concrete type is generally
+// unexported and has no exported fields.  It is lossy: only the message
+// survives, so the error that Err returns has the same text as the
+// original but none of its type or wrapped errors.  The empty Error
+// stands for a nil error.
+type Error string
+
+// NewError returns the Error holding err's message.
+func NewError(err error) Error {
+	if err == nil {
+		return ""
+	}
+	return Error(err.Error())
+}
+
+// Err returns an error with the message held by e, or nil if e is empty.
+func (e Error) Err() error {
+	if e == "" {
+		return nil
+	}
+	return errors.New(string(e))
+}
+
+// A Machine is a Thing that remembers the last error it ran into.
+type Machine struct {
+	MyColor string
+	MyName  string
+	LastErr Error
+}
+
+func (m *Machine) Color() string     { return m.MyColor }
+func (m *Machine) Name() string      { return m.MyName }
+func (m *Machine) setColor(c string) { m.MyColor = c }
+
+func init() {
+	Register("robot", func() Thing {
+		return &Machine{"silver", "robot", NewError(errors.New("boom"))}
+	})
+}
@@ -0,0 +1,50 @@
+package things
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestMachineError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"boom", errors.New("boom")},
+		{"wrapped", fmt.Errorf("starting motor: %w", errors.New("boom"))},
+		{"quoted", errors.New(`bad "input"`)},
+		{"nil", nil},
+	}
+	for _, tc := range tests {
+		m := &Machine{"silver", "robot", NewError(tc.err)}
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(m, s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			thing, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			got := thing.(*Machine).LastErr.Err()
+			if tc.err == nil {
+				if got != nil {
+					t.Errorf("%s, %s: got error %q, want nil", tc.name, s.Name, got)
+				}
+				continue
+			}
+			if got == nil || got.Error() != tc.err.Error() {
+				t.Errorf("%s, %s: got error %v, want %q", tc.name, s.Name, got, tc.err)
+			}
+		}
+	}
+	if err := MustMake("robot").(*Machine).LastErr.Err(); err == nil || err.Error() != "boom" {
+		t.Errorf("robot: got error %v, want boom", err)
+	}
+}
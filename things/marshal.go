@@ -0,0 +1,279 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zson"
+)
+
+// isNil reports whether t is nil or a nil pointer.  The bulk helpers
+// reject such elements with an error naming their position rather than
+// emitting a null that cannot be told apart from a missing Thing.
+func isNil(t Thing) bool {
+	if t == nil {
+		return true
+	}
+	v := reflect.ValueOf(t)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// MarshalThings marshals a slice of Things as a single ZSON value, a
+// List, so each element carries its own decorator and the concrete types
+// can be recovered on unmarshal.  A nil element is an error.  An empty
+// slice, which a List would marshal as null, is marshaled as [].
+func MarshalThings(m *zson.MarshalContext, things []Thing) (string, error) {
+	if len(things) == 0 {
+		return m.Marshal([]string{})
+	}
+	for k, thing := range things {
+		if isNil(thing) {
+			return "", fmt.Errorf("element %d is a nil Thing", k)
+		}
+	}
+	return m.Marshal(List(things))
+}
+
+// UnmarshalThings unmarshals the output of MarshalThings.  The types of
+// the elements must have been bound to u.  An empty list yields an
+// empty, non-nil slice.
+func UnmarshalThings(u *zson.UnmarshalContext, s string) ([]Thing, error) {
+	var things List
+	if err := UnmarshalInto(u, s, &things); err != nil {
+		return nil, err
+	}
+	if things == nil {
+		things = []Thing{}
+	}
+	return things, nil
+}
+
+// An entry holds a key of a map of Things and its value.
+type entry struct {
+	Key   string
+	Value Thing
+}
+
+// entries marshals itself as a List does, so that the values of a map
+// may differ in type.
+type entries []entry
+
+func (e entries) MarshalZNG(m *zson.MarshalZNGContext) (zed.Type, error) {
+	return marshalArray(m, len(e), func(k int) interface{} { return e[k] })
+}
+
+func (e *entries) UnmarshalZNG(u *zson.UnmarshalZNGContext, zv *zed.Value) error {
+	*e = nil
+	return unmarshalArray(zv, func(k int, elem *zed.Value) error {
+		var ent entry
+		if err := unmarshalEntry(u, k, elem, &ent); err != nil {
+			return err
+		}
+		*e = append(*e, ent)
+		return nil
+	})
+}
+
+// unmarshalEntry unmarshals the kth element of a list of entries into v.
+func unmarshalEntry(u *zson.UnmarshalZNGContext, k int, elem *zed.Value, v interface{}) error {
+	if elem == nil {
+		return fmt.Errorf("%w: element %d is null, not an entry", ErrBadInput, k)
+	}
+	if err := unmarshalValue(u, elem, v); err != nil {
+		return fmt.Errorf("element %d: %w", k, err)
+	}
+	return nil
+}
+
+// MarshalThingMap marshals a map of Things as a ZSON list of {Key,Value}
+// records sorted by key, so the output does not depend on Go's map
+// iteration order.  Each value is marshaled as its own concrete type, as
+// an element of a List is.  A nil value is an error.
+func MarshalThingMap(m *zson.MarshalContext, things map[string]Thing) (string, error) {
+	keys := make([]string, 0, len(things))
+	for key, thing := range things {
+		if isNil(thing) {
+			return "", fmt.Errorf("key %q has a nil Thing", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make(entries, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, entry{key, things[key]})
+	}
+	return m.Marshal(list)
+}
+
+// UnmarshalThingMap unmarshals the output of MarshalThingMap.
+func UnmarshalThingMap(u *zson.UnmarshalContext, s string) (map[string]Thing, error) {
+	var list entries
+	if err := UnmarshalInto(u, s, &list); err != nil {
+		return nil, err
+	}
+	things := make(map[string]Thing, len(list))
+	for _, e := range list {
+		things[e.Key] = e.Value
+	}
+	return things, nil
+}
+
+type intEntry struct {
+	Key   int
+	Value Thing
+}
+
+type intEntries []intEntry
+
+func (e intEntries) MarshalZNG(m *zson.MarshalZNGContext) (zed.Type, error) {
+	return marshalArray(m, len(e), func(k int) interface{} { return e[k] })
+}
+
+func (e *intEntries) UnmarshalZNG(u *zson.UnmarshalZNGContext, zv *zed.Value) error {
+	*e = nil
+	return unmarshalArray(zv, func(k int, elem *zed.Value) error {
+		var ent intEntry
+		if err := unmarshalEntry(u, k, elem, &ent); err != nil {
+			return err
+		}
+		*e = append(*e, ent)
+		return nil
+	})
+}
+
+// MarshalIntThingMap marshals a map of Things with integer keys as a ZSON
+// list of {Key,Value} records sorted by key.  Like MarshalThingMap, it
+// uses this list encoding rather than zson's handling of Go maps so that
+// the output is deterministic.  A nil value is an error.
+func MarshalIntThingMap(m *zson.MarshalContext, things map[int]Thing) (string, error) {
+	keys := make([]int, 0, len(things))
+	for key, thing := range things {
+		if isNil(thing) {
+			return "", fmt.Errorf("key %d has a nil Thing", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	list := make(intEntries, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, intEntry{key, things[key]})
+	}
+	return m.Marshal(list)
+}
+
+// UnmarshalIntThingMap unmarshals the output of MarshalIntThingMap.
+func UnmarshalIntThingMap(u *zson.UnmarshalContext, s string) (map[int]Thing, error) {
+	var list intEntries
+	if err := UnmarshalInto(u, s, &list); err != nil {
+		return nil, err
+	}
+	things := make(map[int]Thing, len(list))
+	for _, e := range list {
+		things[e.Key] = e.Value
+	}
+	return things, nil
+}
+
+type sliceEntry struct {
+	Key   string
+	Value List
+}
+
+type sliceEntries []sliceEntry
+
+func (e sliceEntries) MarshalZNG(m *zson.MarshalZNGContext) (zed.Type, error) {
+	return marshalArray(m, len(e), func(k int) interface{} { return e[k] })
+}
+
+func (e *sliceEntries) UnmarshalZNG(u *zson.UnmarshalZNGContext, zv *zed.Value) error {
+	*e = nil
+	return unmarshalArray(zv, func(k int, elem *zed.Value) error {
+		var ent sliceEntry
+		if err := unmarshalEntry(u, k, elem, &ent); err != nil {
+			return err
+		}
+		*e = append(*e, ent)
+		return nil
+	})
+}
+
+// MarshalThingSliceMap marshals a map of Thing slices, such as Things by
+// region, as a ZSON list of {Key,Value} records sorted by key.  Each
+// slice is marshaled as a List, so it keeps its order and each of its
+// elements its own type.  A nil element is an error.
+func MarshalThingSliceMap(m *zson.MarshalContext, things map[string][]Thing) (string, error) {
+	keys := make([]string, 0, len(things))
+	for key, ts := range things {
+		for k, thing := range ts {
+			if isNil(thing) {
+				return "", fmt.Errorf("key %q element %d is a nil Thing", key, k)
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make(sliceEntries, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, sliceEntry{key, things[key]})
+	}
+	return m.Marshal(list)
+}
+
+// UnmarshalThingSliceMap unmarshals the output of MarshalThingSliceMap.
+func UnmarshalThingSliceMap(u *zson.UnmarshalContext, s string) (map[string][]Thing, error) {
+	var list sliceEntries
+	if err := UnmarshalInto(u, s, &list); err != nil {
+		return nil, err
+	}
+	things := make(map[string][]Thing, len(list))
+	for _, e := range list {
+		things[e.Key] = e.Value
+	}
+	return things, nil
+}
+
+// ctxCheckInterval is how many elements MarshalThingsCtx marshals between
+// checks for cancellation.
+const ctxCheckInterval = 1024
+
+// MarshalThingsCtx writes each of things to w as its own line of ZSON,
+// marshaled with a new marshaler from newMarshaler.
+// It checks ctx every ctxCheckInterval elements and, if ctx is done,
+// stops and returns ctx.Err().  A nil element is an error.  The number of
+// Things written is returned in either case.
+func MarshalThingsCtx(ctx context.Context, w io.Writer, newMarshaler MarshalerFunc, things []Thing) (int, error) {
+	return EncodeThingsCtx(ctx, NewEncoderWith(w, newMarshaler), things)
+}
+
+// EncodeThingsCtx is like MarshalThingsCtx but writes with enc, which
+// may have been configured with SetProgress.
+func EncodeThingsCtx(ctx context.Context, enc *Encoder, things []Thing) (int, error) {
+	return EncodeFuncCtx(ctx, enc, len(things), func(k int) (Thing, error) {
+		return things[k], nil
+	})
+}
+
+// EncodeFuncCtx is like EncodeThingsCtx but writes the n Things returned
+// by next in turn, so they need not all be held in memory at once.  An
+// error from next stops the encoding and is returned.
+func EncodeFuncCtx(ctx context.Context, enc *Encoder, n int, next func(k int) (Thing, error)) (int, error) {
+	for k := 0; k < n; k++ {
+		if k%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return k, err
+			}
+		}
+		thing, err := next(k)
+		if err != nil {
+			return k, err
+		}
+		if err := enc.Encode(thing); err != nil {
+			return k, fmt.Errorf("element %d: %w", k, err)
+		}
+	}
+	return n, nil
+}
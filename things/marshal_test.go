@@ -0,0 +1,243 @@
+package things
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestMarshalThings(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{"empty", nil},
+		{"one", []string{"rose"}},
+		{"mixed types", []string{"rose", "ivy", "flamingo", "quartz"}},
+		{"last type differs", []string{"rose", "ivy", "emerald"}},
+		{"types with the same fields", []string{"rose", "flamingo", "rose", "swatch", "sapphire", "swatch"}},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			want := []Thing{}
+			for _, name := range tc.names {
+				want = append(want, MustMake(name))
+			}
+			m := zson.NewMarshaler()
+			m.Decorate(s.Style)
+			zs, err := MarshalThings(m, want)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			u, err := newUnmarshaler()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(want) == 0 && zs != "[]" {
+				t.Errorf("%s, %s: marshaled an empty slice as %s", tc.name, s.Name, zs)
+			}
+			if strings.Contains(zs, `"{`) {
+				t.Errorf("%s, %s: marshaled an element as a string: %s", tc.name, s.Name, zs)
+			}
+			got, err := UnmarshalThings(u, zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			if got == nil || len(got) != len(want) {
+				t.Fatalf("%s, %s: unmarshaled %d Things, want %d", tc.name, s.Name, len(got), len(want))
+			}
+			for k := range want {
+				if equal, err := Equal(got[k], want[k]); err != nil || !equal {
+					t.Errorf("%s, %s: element %d is %s, want %s", tc.name, s.Name, k, Describe(got[k]), Describe(want[k]))
+				}
+			}
+		}
+	}
+}
+
+func TestMarshalThingsNil(t *testing.T) {
+	if _, err := MarshalThings(zson.NewMarshaler(), []Thing{MustMake("rose"), nil}); err == nil {
+		t.Error("marshaling a nil element succeeded")
+	}
+}
+
+func TestThingMap(t *testing.T) {
+	tests := []struct {
+		name   string
+		things map[string]Thing
+	}{
+		{"empty", map[string]Thing{}},
+		{"one", map[string]Thing{"a": MustMake("rose")}},
+		{"mixed types", map[string]Thing{"a": MustMake("rose"), "b": MustMake("flamingo"), "c": MustMake("quartz")}},
+		{"types with the same fields", map[string]Thing{"a": MustMake("rose"), "b": MustMake("flamingo"), "c": MustMake("ivy")}},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			m := zson.NewMarshaler()
+			m.Decorate(s.Style)
+			zs, err := MarshalThingMap(m, tc.things)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			u, err := newUnmarshaler()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := UnmarshalThingMap(u, zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			if len(got) != len(tc.things) {
+				t.Fatalf("%s, %s: unmarshaled %d Things, want %d", tc.name, s.Name, len(got), len(tc.things))
+			}
+			for key, want := range tc.things {
+				if equal, err := Equal(got[key], want); err != nil || !equal {
+					t.Errorf("%s, %s: key %q is %s, want %s", tc.name, s.Name, key, Describe(got[key]), Describe(want))
+				}
+			}
+		}
+	}
+	if _, err := MarshalThingMap(zson.NewMarshaler(), map[string]Thing{"a": nil}); err == nil {
+		t.Error("marshaling a nil value succeeded")
+	}
+}
+
+func TestIntThingMap(t *testing.T) {
+	tests := []struct {
+		name   string
+		things map[int]Thing
+	}{
+		{"empty", map[int]Thing{}},
+		{"mixed types", map[int]Thing{1: MustMake("rose"), 2: MustMake("flamingo"), 10: MustMake("quartz")}},
+		{"negative keys", map[int]Thing{-1: MustMake("ivy"), 0: MustMake("emerald")}},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			m := zson.NewMarshaler()
+			m.Decorate(s.Style)
+			zs, err := MarshalIntThingMap(m, tc.things)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			u, err := newUnmarshaler()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := UnmarshalIntThingMap(u, zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			if len(got) != len(tc.things) {
+				t.Fatalf("%s, %s: unmarshaled %d Things, want %d", tc.name, s.Name, len(got), len(tc.things))
+			}
+			for key, want := range tc.things {
+				if equal, err := Equal(got[key], want); err != nil || !equal {
+					t.Errorf("%s, %s: key %d is %s, want %s", tc.name, s.Name, key, Describe(got[key]), Describe(want))
+				}
+			}
+		}
+	}
+	if _, err := MarshalIntThingMap(zson.NewMarshaler(), map[int]Thing{1: nil}); err == nil {
+		t.Error("marshaling a nil value succeeded")
+	}
+}
+
+func TestThingSliceMap(t *testing.T) {
+	regions := map[string][]string{
+		"north": {"rose", "flamingo"},
+		"south": {"ivy", "quartz", "emerald"},
+		"empty": {},
+	}
+	for _, s := range Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		want := make(map[string][]Thing)
+		for key, names := range regions {
+			want[key] = []Thing{}
+			for _, name := range names {
+				want[key] = append(want[key], MustMake(name))
+			}
+		}
+		m := zson.NewMarshaler()
+		m.Decorate(s.Style)
+		zs, err := MarshalThingSliceMap(m, want)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		u, err := newUnmarshaler()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := UnmarshalThingSliceMap(u, zs)
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", s.Name, err, zs)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: unmarshaled %d regions, want %d", s.Name, len(got), len(want))
+		}
+		for key, ts := range want {
+			if len(got[key]) != len(ts) {
+				t.Fatalf("%s: region %q has %d Things, want %d", s.Name, key, len(got[key]), len(ts))
+			}
+			for k := range ts {
+				if equal, err := Equal(got[key][k], ts[k]); err != nil || !equal {
+					t.Errorf("%s: region %q element %d is %s, want %s", s.Name, key, k, Describe(got[key][k]), Describe(ts[k]))
+				}
+			}
+		}
+	}
+	if _, err := MarshalThingSliceMap(zson.NewMarshaler(), map[string][]Thing{"a": {MustMake("rose"), nil}}); err == nil {
+		t.Error("marshaling a nil element succeeded")
+	}
+}
+
+func TestEncodeFuncCtx(t *testing.T) {
+	names := []string{"rose", "flamingo", "quartz"}
+	next := func(k int) (Thing, error) {
+		if k == len(names) {
+			return nil, errors.New("no more")
+		}
+		return Make(names[k])
+	}
+	tests := []struct {
+		name     string
+		n        int
+		canceled bool
+		written  int
+		fail     bool
+	}{
+		{"none", 0, false, 0, false},
+		{"all", 3, false, 3, false},
+		{"next fails", 5, false, 3, true},
+		{"canceled", 3, true, 0, true},
+	}
+	for _, tc := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.canceled {
+			cancel()
+		}
+		var b bytes.Buffer
+		n, err := EncodeFuncCtx(ctx, NewEncoder(&b, zson.StyleSimple), tc.n, next)
+		cancel()
+		if n != tc.written || (err != nil) != tc.fail {
+			t.Errorf("%s: got %d, %v; want %d Things written", tc.name, n, err, tc.written)
+		}
+		if lines := bytes.Count(b.Bytes(), []byte("\n")); lines != tc.written {
+			t.Errorf("%s: wrote %d lines, want %d", tc.name, lines, tc.written)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package things
+
+type Mineral struct {
+	MyColor  string
+	MyName   string
+	Hardness int
+}
+
+func (m *Mineral) Color() string { return m.MyColor }
+func (m *Mineral) Name() string  { return m.MyName }
+
+func (m *Mineral) setColor(c string) { m.MyColor = c }
+
+func init() {
+	Register("quartz", func() Thing { return &Mineral{"white", "quartz", 7} })
+	Register("diamond", func() Thing { return &Mineral{"clear", "diamond", 10} })
+}
@@ -0,0 +1,40 @@
+package things
+
+import (
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestMineralRoundtrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    string
+		hardness int
+	}{
+		{"diamond", "clear", 10},
+		{"quartz", "white", 7},
+	}
+	for _, tc := range tests {
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(MustMake(tc.name), s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", tc.name, s.Name, err)
+			}
+			thing, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s\n%s", tc.name, s.Name, err, zs)
+			}
+			m, ok := thing.(*Mineral)
+			if !ok {
+				t.Fatalf("%s, %s: unmarshaled a %T", tc.name, s.Name, thing)
+			}
+			if m.Color() != tc.color || m.Hardness != tc.hardness {
+				t.Errorf("%s, %s: got color %q and hardness %d, want %q and %d", tc.name, s.Name, m.Color(), m.Hardness, tc.color, tc.hardness)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package things
+
+import "reflect"
+
+// NullAsEmpty sets every nil *string field of t, such as the color of a
+// Swatch, including those of nested structs, to point to an empty
+// string, so that a null in the input reads as "" rather than as a
+// missing value.  A null unmarshaled into a plain string field already
+// yields "", so only pointer fields are affected.
+func NullAsEmpty(t Thing) {
+	v := reflect.ValueOf(t)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct && v.CanSet() {
+		nullAsEmpty(v)
+	}
+}
+
+func nullAsEmpty(v reflect.Value) {
+	for k := 0; k < v.NumField(); k++ {
+		f := v.Field(k)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.String:
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+		case f.Kind() == reflect.Struct:
+			nullAsEmpty(f)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package things
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+type holder struct {
+	Thing Thing
+}
+
+func TestNilThings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		null  string
+	}{
+		{"nil Contents", &Garden{MyName: "empty"}, "Contents:null"},
+		{"nil element", &Garden{MyName: "gap", Contents: List{nil}}, "Contents:[null]"},
+		{"nil Thing field", &holder{}, "Thing:null"},
+	}
+	for _, tc := range tests {
+		m := zson.NewMarshaler()
+		m.Decorate(zson.StyleSimple)
+		s, err := m.Marshal(tc.value)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if !strings.Contains(s, tc.null) {
+			t.Errorf("%s: %s does not contain %s", tc.name, s, tc.null)
+		}
+		u, err := newUnmarshaler()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u.Bind(holder{})
+		switch tc.value.(type) {
+		case *Garden:
+			g := Garden{Contents: List{MustMake("rose")}}
+			if err := UnmarshalInto(u, s, &g); err != nil {
+				t.Fatalf("%s: %s", tc.name, err)
+			}
+			want := tc.value.(*Garden).Contents
+			if (g.Contents == nil) != (want == nil) || len(g.Contents) != len(want) {
+				t.Errorf("%s: unmarshaled Contents %v, want %v", tc.name, g.Contents, want)
+			}
+			for k, thing := range g.Contents {
+				if thing != nil {
+					t.Errorf("%s: element %d is %s, want nil", tc.name, k, Describe(thing))
+				}
+			}
+		case *holder:
+			h := holder{Thing: MustMake("rose")}
+			if err := UnmarshalInto(u, s, &h); err != nil {
+				t.Fatalf("%s: %s", tc.name, err)
+			}
+			if h.Thing != nil {
+				t.Errorf("%s: unmarshaled %s, want nil", tc.name, Describe(h.Thing))
+			}
+		}
+	}
+}
+
+func TestNullAsEmpty(t *testing.T) {
+	const (
+		null  = `{MyColor:null(string),MyName:"swatch"}(=Swatch)`
+		color = `{MyColor:"red",MyName:"swatch"}(=Swatch)`
+	)
+	tests := []struct {
+		name     string
+		input    string
+		empty    bool
+		wantNil  bool
+		wantText string
+	}{
+		{"null", null, false, true, ""},
+		{"null as empty", null, true, false, ""},
+		{"color", color, false, false, "red"},
+		{"color as empty", color, true, false, "red"},
+	}
+	for _, tc := range tests {
+		thing, err := ZSONToThing(tc.input)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if tc.empty {
+			NullAsEmpty(thing)
+		}
+		s := thing.(*Swatch)
+		if (s.MyColor == nil) != tc.wantNil {
+			t.Errorf("%s: MyColor nil is %t, want %t", tc.name, s.MyColor == nil, tc.wantNil)
+		}
+		if s.Color() != tc.wantText {
+			t.Errorf("%s: Color is %q, want %q", tc.name, s.Color(), tc.wantText)
+		}
+	}
+}
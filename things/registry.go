@@ -0,0 +1,197 @@
+package things
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/brimdata/zed/zson"
+)
+
+var registry = map[string]func() Thing{}
+
+// boundTypes holds the type BindAll binds for each registered name,
+// which is that of the first Thing its constructor makes, with any
+// pointer removed.
+var boundTypes = map[string]reflect.Type{}
+
+// Register makes a Thing constructor available to Make under the given name.
+// It is intended to be called from init functions, including those of
+// packages that define their own Thing types.  Register calls ctor once
+// to learn the type, ignoring pointers, that BindAll binds for name.
+func Register(name string, ctor func() Thing) {
+	if _, ok := registry[name]; ok {
+		panic("things: Register called twice for " + name)
+	}
+	typ := baseType(ctor())
+	if typ == nil {
+		panic("things: Register: constructor for " + name + " made a nil Thing")
+	}
+	registry[name] = ctor
+	boundTypes[name] = typ
+}
+
+func Make(which string) (Thing, error) {
+	return MakeColored(which, "")
+}
+
+// colorSetter is implemented by Things whose color MakeColored can
+// override.
+type colorSetter interface {
+	setColor(string)
+}
+
+// MakeColored is like Make but, if color is not empty, gives the Thing
+// that color instead of its usual one, e.g., a blue rose.  A constructor
+// that makes a Thing of a different type than it did when registered is
+// an error wrapping ErrUnboundType, since values of that type would not
+// be bound for unmarshaling.
+func MakeColored(which, color string) (Thing, error) {
+	ctor, ok := registry[which]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownThing, which)
+	}
+	t := ctor()
+	if typ := baseType(t); typ != boundTypes[which] {
+		return nil, fmt.Errorf("%w: constructor for %q made %v, but the type bound for it is %s", ErrUnboundType, which, typ, boundTypes[which])
+	}
+	if color != "" {
+		cs, ok := t.(colorSetter)
+		if !ok {
+			return nil, fmt.Errorf("thing %q does not support a color override", which)
+		}
+		cs.setColor(color)
+	}
+	return t, nil
+}
+
+// MustMake is like Make but panics if which is not a registered name.
+// It is intended for tests and initialization code, not for handling
+// names that come from user input.
+func MustMake(which string) Thing {
+	t, err := Make(which)
+	if err != nil {
+		panic("things: MustMake: " + err.Error())
+	}
+	return t
+}
+
+// Names returns the registered Thing names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unregistered holds templates of Thing types that have no constructor
+// in the registry but must still be bound, such as Garden.
+var unregistered []interface{}
+
+// bindOnly arranges for BindAll to bind the type of template without
+// making it available to Make.
+func bindOnly(template interface{}) {
+	unregistered = append(unregistered, template)
+}
+
+// baseType returns the type of v with any pointer removed.
+func baseType(v interface{}) reflect.Type {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// templates returns a zero value of each distinct type in boundTypes,
+// followed by those given to bindOnly, suitable for passing to Bind.
+func templates() []interface{} {
+	seen := make(map[reflect.Type]bool)
+	var out []interface{}
+	for _, name := range Names() {
+		typ := boundTypes[name]
+		if !seen[typ] {
+			seen[typ] = true
+			out = append(out, reflect.Zero(typ).Interface())
+		}
+	}
+	return append(out, unregistered...)
+}
+
+// BindAll binds every registered Thing type to u so that values of any
+// of them can be unmarshaled into a Thing.  Like zson's Bind, it binds
+// each type under the decorators of StyleSimple, StylePackage, and
+// StyleFull, e.g., Plant, things.Plant, and github.com/.../things.Plant,
+// but it leaves out any of these names that two registered types share,
+// since u could not tell their values apart.  Unmarshaling a value
+// decorated with such a name then fails with an error wrapping
+// ErrAmbiguousType, while values decorated under a style that does tell
+// the types apart unmarshal as usual.
+func BindAll(u *zson.UnmarshalContext) error {
+	var bindings []zson.Binding
+	for name, types := range bindNames() {
+		if len(types) == 1 {
+			bindings = append(bindings, zson.Binding{Name: name, Template: reflect.Zero(types[0]).Interface()})
+		}
+	}
+	return u.NamedBindings(bindings)
+}
+
+// bindNames returns the distinct types that BindAll would bind under each
+// decorator name, following the naming of zson's Bind.
+func bindNames() map[string][]reflect.Type {
+	names := make(map[string][]reflect.Type)
+	for _, t := range templates() {
+		typ := baseType(t)
+		path := strings.Split(typ.PkgPath(), "/")
+		for _, name := range []string{
+			typ.Name(),
+			path[len(path)-1] + "." + typ.Name(),
+			typ.PkgPath() + "." + typ.Name(),
+		} {
+			names[name] = append(names[name], typ)
+		}
+	}
+	return names
+}
+
+// ambiguousType returns an error wrapping ErrAmbiguousType if name is a
+// decorator that BindAll left unbound because more than one registered
+// type answers to it, or nil otherwise.
+func ambiguousType(name string) error {
+	types := bindNames()[name]
+	if len(types) < 2 {
+		return nil
+	}
+	return fmt.Errorf("%w %q: registered by both %s and %s; use a more detailed style or NamedBindings to tell them apart",
+		ErrAmbiguousType, name, types[0].PkgPath(), types[1].PkgPath())
+}
+
+func newUnmarshaler() (*zson.UnmarshalContext, error) {
+	u := zson.NewUnmarshaler()
+	if err := BindAll(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// PrefixBindings returns a named binding for each registered type that
+// renames it prefix.TypeName, e.g., myorg.Plant.
+func PrefixBindings(prefix string) []zson.Binding {
+	return DecoratorBindings(func(typ reflect.Type) string {
+		return prefix + "." + typ.Name()
+	})
+}
+
+// TypeNames returns the names of the distinct types of the registered
+// Things, which are the types BindAll binds.
+func TypeNames() []string {
+	var names []string
+	for _, t := range templates() {
+		names = append(names, reflect.TypeOf(t).Name())
+	}
+	return names
+}
@@ -0,0 +1,235 @@
+package things
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestMake(t *testing.T) {
+	// Every registered name must appear here, so a new Thing needs a
+	// new row.
+	tests := []struct {
+		name  string
+		typ   string
+		color string
+	}{
+		{"diamond", "*things.Mineral", "clear"},
+		{"emerald", "*things.Gem", "green"},
+		{"flamingo", "*things.Animal", "pink"},
+		{"ivy", "*things.Plant", "green"},
+		{"quartz", "*things.Mineral", "white"},
+		{"robot", "*things.Machine", "silver"},
+		{"rose", "*things.Plant", "red"},
+		{"sapphire", "*things.Gem", "blue"},
+		{"sunrise", "*things.Event", "orange"},
+		// A Swatch is made without a color.
+		{"swatch", "*things.Swatch", ""},
+	}
+	names := Names()
+	if len(names) != len(tests) {
+		t.Fatalf("registered names are %v; the table has %d rows", names, len(tests))
+	}
+	for k, tc := range tests {
+		if names[k] != tc.name {
+			t.Fatalf("registered name %d is %q, want %q; update the table", k, names[k], tc.name)
+		}
+		thing, err := Make(tc.name)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if isNil(thing) {
+			t.Fatalf("%s: made a nil Thing", tc.name)
+		}
+		if typ := fmt.Sprintf("%T", thing); typ != tc.typ {
+			t.Errorf("%s: made a %s, want a %s", tc.name, typ, tc.typ)
+		}
+		if thing.Color() != tc.color {
+			t.Errorf("%s: color is %q, want %q", tc.name, thing.Color(), tc.color)
+		}
+		if thing.Name() != tc.name {
+			t.Errorf("%s: name is %q", tc.name, thing.Name())
+		}
+	}
+	for _, name := range []string{"", "unknown", "Rose"} {
+		thing, err := Make(name)
+		if thing != nil || !errors.Is(err, ErrUnknownThing) {
+			t.Errorf("Make(%q) = %v, %v; want an unknown thing error", name, thing, err)
+		}
+	}
+	defer func() {
+		want := `things: MustMake: unknown thing "nonesuch"`
+		if r := recover(); fmt.Sprint(r) != want {
+			t.Errorf("MustMake(%q): got panic %v, want %q", "nonesuch", r, want)
+		}
+	}()
+	MustMake("nonesuch")
+}
+
+func TestMakeColored(t *testing.T) {
+	tests := []struct {
+		name, color string
+		want        string
+		ok          bool
+	}{
+		{"rose", "", "red", true},
+		{"rose", "blue", "blue", true},
+		{"quartz", "smoky", "smoky", true},
+		{"swatch", "teal", "teal", true},
+		{"nonesuch", "red", "", false},
+	}
+	for _, tc := range tests {
+		thing, err := MakeColored(tc.name, tc.color)
+		if !tc.ok {
+			if err == nil {
+				t.Errorf("%s %s: made %s, want an error", tc.name, tc.color, Describe(thing))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: %s", tc.name, tc.color, err)
+		} else if thing.Color() != tc.want {
+			t.Errorf("%s %s: color is %q, want %q", tc.name, tc.color, thing.Color(), tc.want)
+		}
+	}
+}
+
+func TestUnmarshalIsPointer(t *testing.T) {
+	for _, name := range Names() {
+		want := MustMake(name)
+		if reflect.TypeOf(want).Kind() != reflect.Ptr {
+			t.Errorf("%s: Make returned a %T, not a pointer", name, want)
+		}
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(&Garden{MyName: "bed", Contents: List{want}}, s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", name, s.Name, err)
+			}
+			garden, err := ZSONToThing(zs)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", name, s.Name, err)
+			}
+			for _, got := range []Thing{garden, garden.(*Garden).Contents[0]} {
+				if reflect.TypeOf(got).Kind() != reflect.Ptr {
+					t.Errorf("%s, %s: unmarshaled a %T, not a pointer", name, s.Name, got)
+				}
+			}
+			if got := garden.(*Garden).Contents[0]; reflect.TypeOf(got) != reflect.TypeOf(want) {
+				t.Errorf("%s, %s: unmarshaled a %T, want a %T", name, s.Name, got, want)
+			}
+		}
+	}
+}
+
+// registerTemp registers ctor under name for the rest of the test.
+func registerTemp(t *testing.T, name string, ctor func() Thing) {
+	t.Helper()
+	Register(name, ctor)
+	t.Cleanup(func() {
+		delete(registry, name)
+		delete(boundTypes, name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	// The type bound for each name is the one its constructor makes.
+	for _, name := range Names() {
+		if typ := baseType(MustMake(name)); typ != boundTypes[name] {
+			t.Errorf("%s: constructor makes %s, but %s is bound", name, typ, boundTypes[name])
+		}
+	}
+	registerTemp(t, "pebble", func() Thing { return &Mineral{"grey", "pebble", 3} })
+	if typ := boundTypes["pebble"]; typ != reflect.TypeOf(Mineral{}) {
+		t.Errorf("pebble: bound %s, want things.Mineral", typ)
+	}
+	tests := []struct {
+		name string
+		ctor func() Thing
+		want string
+	}{
+		{"rose", func() Thing { return &Plant{} }, "things: Register called twice for rose"},
+		{"void", func() Thing { return nil }, "things: Register: constructor for void made a nil Thing"},
+	}
+	for _, tc := range tests {
+		func() {
+			defer func() {
+				if r := recover(); fmt.Sprint(r) != tc.want {
+					t.Errorf("%s: got panic %v, want %q", tc.name, r, tc.want)
+				}
+			}()
+			registerTemp(t, tc.name, tc.ctor)
+		}()
+	}
+}
+
+func TestMakeUnboundType(t *testing.T) {
+	// A constructor that makes a Mineral when registered and a Gem
+	// after that, like a wrapper that changes what it returns.
+	calls := 0
+	registerTemp(t, "changeling", func() Thing {
+		calls++
+		if calls == 1 {
+			return &Mineral{"grey", "changeling", 1}
+		}
+		return &Gem{"grey", "changeling"}
+	})
+	_, err := Make("changeling")
+	if !errors.Is(err, ErrUnboundType) {
+		t.Fatalf("got %v, want an unbound type error", err)
+	}
+	for _, s := range []string{`"changeling"`, "things.Gem", "things.Mineral"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error %q does not name %s", err, s)
+		}
+	}
+}
+
+// A pebble is a Thing known only to TestBindAllDerived.
+type pebble struct {
+	Shade string
+}
+
+func (p *pebble) Color() string { return p.Shade }
+func (p *pebble) Name() string  { return "pebble" }
+
+func TestBindAllDerived(t *testing.T) {
+	// Registering a constructor is all it takes for BindAll to bind
+	// the type it makes.
+	registerTemp(t, "pebble", func() Thing { return &pebble{"grey"} })
+	seen := make(map[reflect.Type]bool)
+	for _, tmpl := range templates() {
+		typ := reflect.TypeOf(tmpl)
+		if typ.Kind() == reflect.Ptr || seen[typ] {
+			t.Errorf("template %T is a pointer or a duplicate", tmpl)
+		}
+		seen[typ] = true
+	}
+	for _, name := range Names() {
+		thing := MustMake(name)
+		if !seen[baseType(thing)] {
+			t.Errorf("%s: no template for %T", name, thing)
+		}
+		for _, s := range Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			zs, err := ThingToZSON(thing, s.Style)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", name, s.Name, err)
+			}
+			got, err := ZSONToThing(zs)
+			if err != nil {
+				t.Errorf("%s, %s: %s", name, s.Name, err)
+			} else if reflect.TypeOf(got) != reflect.TypeOf(thing) {
+				t.Errorf("%s, %s: unmarshaled a %T, want a %T", name, s.Name, got, thing)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package things
+
+import (
+	"errors"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// A MarshalerFunc returns a new marshaler configured as its caller
+// wishes, e.g., with a style and named bindings.  Code that marshals
+// many values takes a MarshalerFunc rather than a marshaler, since a
+// zson.MarshalContext writes each type definition only the first time it
+// formats the type, so a value from a reused marshaler may not be
+// readable on its own.
+type MarshalerFunc func() *zson.MarshalContext
+
+// A SafeMarshaler marshals Things for multiple goroutines.  A
+// zson.MarshalContext is not safe for concurrent use, since it records
+// the types it has seen as it marshals, so SafeMarshaler shares none and
+// gets a new marshaler for each call instead.
+type SafeMarshaler struct {
+	newMarshaler MarshalerFunc
+}
+
+// NewSafeMarshaler returns a SafeMarshaler that marshals with the
+// marshalers returned by newMarshaler, which must be safe to call from
+// multiple goroutines.
+func NewSafeMarshaler(newMarshaler MarshalerFunc) *SafeMarshaler {
+	return &SafeMarshaler{newMarshaler: newMarshaler}
+}
+
+// MarshalThing marshals t.  A nil Thing is an error.
+func (s *SafeMarshaler) MarshalThing(t Thing) (string, error) {
+	if isNil(t) {
+		return "", errors.New("cannot marshal a nil Thing")
+	}
+	return MarshalThing(s.newMarshaler(), t)
+}
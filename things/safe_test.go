@@ -0,0 +1,45 @@
+package things
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestSafeMarshalerConcurrent(t *testing.T) {
+	names := Names()
+	want := make(map[string]string)
+	for _, name := range names {
+		s, err := ThingToZSON(MustMake(name), zson.StyleSimple)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[name] = s
+	}
+	sm := NewSafeMarshaler(func() *zson.MarshalContext {
+		m := zson.NewMarshaler()
+		m.Decorate(zson.StyleSimple)
+		return m
+	})
+	var wg sync.WaitGroup
+	errs := make(chan string, 100*len(names))
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			name := names[g%len(names)]
+			s, err := sm.MarshalThing(MustMake(name))
+			if err != nil {
+				errs <- err.Error()
+			} else if s != want[name] {
+				errs <- name + ": " + s
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
@@ -0,0 +1,337 @@
+package things
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zcode"
+	"github.com/brimdata/zed/zson"
+)
+
+// A ValueReader reads the top-level values of a ZSON stream.  Unlike
+// zsonio's Reader, which returns no value and no error when it meets text
+// it cannot parse or a value cut off by the end of the input, it fails
+// unless the whole input, apart from whitespace and comments, is made of
+// well-formed values.  As with zsonio's Reader, a type defined by one
+// value may be used by those after it.  Unlike zsonio's Reader, it also
+// knows where in the input each value begins.
+type ValueReader struct {
+	r        *bufio.Reader
+	text     bytes.Buffer
+	offset   int64
+	line     int
+	pos      Position
+	zctx     *zed.Context
+	analyzer zson.Analyzer
+	builder  *zcode.Builder
+}
+
+// NewValueReader returns a ValueReader that reads from r and creates
+// types in zctx.
+func NewValueReader(r io.Reader, zctx *zed.Context) *ValueReader {
+	return &ValueReader{
+		r:        bufio.NewReader(r),
+		zctx:     zctx,
+		analyzer: zson.NewAnalyzer(),
+		builder:  zcode.NewBuilder(),
+	}
+}
+
+// A Position locates the start of a value in a ZSON stream.
+type Position struct {
+	// Offset is the number of bytes before the value.
+	Offset int64
+	// Line is the number of the line the value starts on, counting
+	// from one.
+	Line int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("at line %d (offset %d)", p.Line, p.Offset)
+}
+
+// Position returns the position of the value most recently returned by
+// Read, or of the one it failed to read.
+func (r *ValueReader) Position() Position {
+	return r.pos
+}
+
+// Read returns the next value in the stream, or nil and a nil error at
+// its end.  A value that is truncated or malformed, or text between
+// values that is not part of any value, is an error wrapping ErrBadInput.
+func (r *ValueReader) Read() (val *zed.Value, err error) {
+	text, err := r.scan()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The parser returns nothing, and no error, for a value cut short
+	// or for text that is not a value, and it stops without complaint
+	// at the end of a prefix that is a value, as with the true of
+	// truex.  Parsing the text as the only element of a list makes it
+	// fail unless the element takes up all of the text.
+	defer func() {
+		// The parser panics on some malformed input, such as an
+		// empty backquoted string.
+		if p := recover(); p != nil {
+			val, err = nil, fmt.Errorf("%w: parse error: %v", ErrBadInput, p)
+		}
+	}()
+	wrapped := append(append([]byte{'['}, text...), "\n]"...)
+	ast, err := zson.NewParser(bytes.NewReader(wrapped)).ParseValue()
+	if err != nil || ast == nil {
+		return nil, invalidValue(text)
+	}
+	v, err := r.analyzer.ConvertValue(r.zctx, ast)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	list, ok := v.(*zson.Array)
+	if !ok || len(list.Elements) != 1 {
+		return nil, invalidValue(text)
+	}
+	zv, err := zson.Build(r.builder, list.Elements[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	return zed.NewValue(zv.Type, zv.Bytes), nil
+}
+
+// invalidValue returns an error for the text of a value that does not
+// parse, using the parser's own description of the problem if it has
+// one.
+func invalidValue(text []byte) error {
+	if _, err := zson.NewParser(bytes.NewReader(text)).ParseValue(); err != nil {
+		return fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	const max = 40
+	if len(text) > max {
+		text = append(text[:max:max], "..."...)
+	}
+	return fmt.Errorf("%w: not a ZSON value: %q", ErrBadInput, text)
+}
+
+// scan returns the text of the next value in the stream, with any type
+// decorators that follow it, or io.EOF if only whitespace and comments
+// remain.  scan does not parse the value; it only follows its brackets
+// and strings far enough to find where it ends.
+func (r *ValueReader) scan() ([]byte, error) {
+	r.text.Reset()
+	if err := r.skipSpace(); err != nil {
+		return nil, err
+	}
+	for {
+		if err := r.scanItem(); err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("%w: truncated value: %s", ErrBadInput, io.ErrUnexpectedEOF)
+			}
+			return nil, err
+		}
+		// A decorator may follow the value after whitespace, as
+		// in pretty-printed output.
+		var space []byte
+		for {
+			b, err := r.peek()
+			if err == io.EOF {
+				return r.text.Bytes(), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if b == '(' {
+				break
+			}
+			if !isSpace(b) {
+				return r.text.Bytes(), nil
+			}
+			r.skip()
+			space = append(space, b)
+		}
+		r.text.Write(space)
+	}
+}
+
+// skipSpace skips the whitespace and comments before a value.
+// It leaves r.pos at the first byte after them.
+func (r *ValueReader) skipSpace() error {
+	for {
+		r.pos = Position{Offset: r.offset, Line: r.line + 1}
+		b, err := r.peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case isSpace(b):
+			r.skip()
+		case b == '/':
+			next, err := r.r.Peek(2)
+			if err != nil || (next[1] != '/' && next[1] != '*') {
+				return nil
+			}
+			r.read()
+			if err := r.scanComment(); err == io.EOF {
+				return fmt.Errorf("%w: unterminated comment", ErrBadInput)
+			} else if err != nil {
+				return err
+			}
+			r.text.Reset()
+		default:
+			return nil
+		}
+	}
+}
+
+// scanItem appends to r.text a value or decorator without the
+// decorators that follow it: a bracketed value, a string, or a run of
+// other text up to the next space, bracket, or quote.
+func (r *ValueReader) scanItem() error {
+	b, err := r.read()
+	if err != nil {
+		return err
+	}
+	switch b {
+	case '{', '[', '(', '<':
+		return r.scanGroup()
+	case '|':
+		// A set, |[...]|, or a map, |{...}|.
+		if next, err := r.peek(); err == nil && (next == '[' || next == '{') {
+			r.read()
+			return r.scanGroup()
+		}
+	case '"', '`':
+		return r.scanString(b)
+	}
+	for {
+		b, err := r.peek()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if isSpace(b) || bytes.IndexByte([]byte("{[(<|\"`"), b) >= 0 {
+			return nil
+		}
+		r.read()
+	}
+}
+
+// scanGroup appends to r.text the rest of a bracketed value whose
+// opening bracket it already holds, including the bar that closes a set
+// or a map.  It does not check that the brackets match in kind, which
+// is left to the parser.
+func (r *ValueReader) scanGroup() error {
+	for depth := 1; depth > 0; {
+		b, err := r.read()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case '{', '[', '(', '<':
+			depth++
+		case '}', ']', ')', '>':
+			depth--
+		case '"', '`':
+			if err := r.scanString(b); err != nil {
+				return err
+			}
+		case '/':
+			if next, err := r.peek(); err == nil && (next == '/' || next == '*') {
+				if err := r.scanComment(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if b, err := r.peek(); err == nil && b == '|' {
+		r.read()
+	}
+	return nil
+}
+
+// scanString appends to r.text the rest of a string whose opening quote
+// it already holds.
+func (r *ValueReader) scanString(quote byte) error {
+	for {
+		b, err := r.read()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case quote:
+			return nil
+		case '\\':
+			if quote == '"' {
+				if _, err := r.read(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// scanComment appends to r.text the rest of a // or /* */ comment whose
+// first slash it already holds.  A // comment ends with its line, so one
+// cut off by the end of the input is given a newline to end it in r.text
+// too.
+func (r *ValueReader) scanComment() error {
+	b, err := r.read()
+	if err != nil {
+		return err
+	}
+	for prev := byte(0); ; {
+		c, err := r.read()
+		if err != nil {
+			if b == '/' && err == io.EOF {
+				r.text.WriteByte('\n')
+				return nil
+			}
+			return err
+		}
+		if b == '/' && c == '\n' || b == '*' && prev == '*' && c == '/' {
+			return nil
+		}
+		prev = c
+	}
+}
+
+// read reads a byte from the stream and appends it to r.text.
+func (r *ValueReader) read() (byte, error) {
+	b, err := r.skip()
+	if err != nil {
+		return 0, err
+	}
+	r.text.WriteByte(b)
+	return b, nil
+}
+
+// skip reads a byte from the stream, keeping count of the bytes and
+// lines read.
+func (r *ValueReader) skip() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	r.offset++
+	if b == '\n' {
+		r.line++
+	}
+	return b, nil
+}
+
+func (r *ValueReader) peek() (byte, error) {
+	b, err := r.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
@@ -0,0 +1,73 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zson"
+)
+
+func TestValueReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		values []string
+		bad    bool
+	}{
+		{"empty", "", nil, false},
+		{"whitespace", " \n\t\n", nil, false},
+		{"one per line", "1\n\"a\"\n{a:1}\n", []string{"1", `"a"`, "{a:1}"}, false},
+		{"one line", `1 "a" {a:1} [1,2] |[3]| |{"k":4}|`, []string{"1", `"a"`, "{a:1}", "[1,2]", "|[3]|", `|{"k":4}|`}, false},
+		{"decorators", "80(port=uint16) {a:1}(=Foo) {a:2}(Foo)", []string{"80(port=uint16)", "{a:1}(=Foo)", "{a:2}(=Foo)"}, false},
+		{"pretty", "{\n    a: 1,\n    b: \"}\"\n} (=Bar)\n", []string{`{a:1,b:"}"}(=Bar)`}, false},
+		{"comments", "// first\n{a:1 /* ] */} // trailing\n/* last */", []string{"{a:1}"}, false},
+		{"type value", "<{a:int64}>", []string{"<{a:int64}>"}, false},
+		{"junk", "junk\n", nil, true},
+		{"junk after a value", "{a:1}\njunk", []string{"{a:1}"}, true},
+		{"value prefix", "truex", nil, true},
+		{"truncated record", "{a:", nil, true},
+		{"truncated second value", "{a:1}\n{a:", []string{"{a:1}"}, true},
+		{"truncated string", `"abc`, nil, true},
+		{"unterminated comment", "{a:1} /* ", []string{"{a:1}"}, true},
+		{"stray bracket", "1}", nil, true},
+		{"two strings", `"a""b"`, []string{`"a"`, `"b"`}, false},
+	}
+	for _, tc := range tests {
+		r := NewValueReader(strings.NewReader(tc.input), zed.NewContext())
+		var values []string
+		var err error
+		for {
+			var val *zed.Value
+			if val, err = r.Read(); err != nil || val == nil {
+				break
+			}
+			values = append(values, zson.MustFormatValue(val))
+		}
+		if strings.Join(values, "\n") != strings.Join(tc.values, "\n") {
+			t.Errorf("%s: read %q, want %q", tc.name, values, tc.values)
+		}
+		if tc.bad && !errors.Is(err, ErrBadInput) {
+			t.Errorf("%s: got %v, want a bad input error", tc.name, err)
+		}
+		if !tc.bad && err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+}
+
+func TestValueReaderPosition(t *testing.T) {
+	input := "{a:1}\n\n  {\n    a: 2\n  } (=Foo) 3\n// note\n{a:\n"
+	want := []Position{{0, 1}, {9, 3}, {31, 5}, {41, 7}}
+	r := NewValueReader(strings.NewReader(input), zed.NewContext())
+	for k, pos := range want {
+		_, err := r.Read()
+		if k == len(want)-1 && !errors.Is(err, ErrBadInput) {
+			t.Errorf("value %d: got %v, want a bad input error", k, err)
+		}
+		if got := r.Position(); got != pos {
+			t.Errorf("value %d: at %+v, want %+v", k, got, pos)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package things
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// A StateMarshaler is a Thing whose state is not in exported fields, so
+// that zson would marshal nothing useful.  MarshalState returns an
+// exported struct holding that state, which ThingToZSON and Encoder
+// marshal instead of the Thing itself, and SetState restores the state
+// from a value of the same type; see UnmarshalState.
+type StateMarshaler interface {
+	Thing
+	MarshalState() interface{}
+	SetState(state interface{}) error
+}
+
+// MarshalThing marshals t with m, or t's state if t is a StateMarshaler.
+func MarshalThing(m *zson.MarshalContext, t Thing) (string, error) {
+	if sm, ok := t.(StateMarshaler); ok {
+		return m.Marshal(sm.MarshalState())
+	}
+	return m.Marshal(t)
+}
+
+// UnmarshalState unmarshals the state of a StateMarshaler marshaled by
+// MarshalThing from s and restores it into t with SetState.  The type of
+// the state is taken from t.MarshalState, so it need not be bound to u.
+func UnmarshalState(u *zson.UnmarshalContext, s string, t StateMarshaler) error {
+	typ := baseType(t.MarshalState())
+	if typ == nil {
+		return errors.New("MarshalState returned nil")
+	}
+	state := reflect.New(typ)
+	if err := UnmarshalInto(u, s, state.Interface()); err != nil {
+		return err
+	}
+	return t.SetState(state.Elem().Interface())
+}
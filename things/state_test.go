@@ -0,0 +1,50 @@
+package things
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestLampState(t *testing.T) {
+	for _, s := range Styles() {
+		lamp := NewLamp("amber", "desk")
+		zs, err := ThingToZSON(lamp, s.Style)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if !strings.Contains(zs, `Color:"amber"`) {
+			t.Errorf("%s: %s does not hold the color", s.Name, zs)
+		}
+		// Marshaled directly, a Lamp has no exported state.
+		m := zson.NewMarshaler()
+		m.Decorate(s.Style)
+		direct, err := m.Marshal(lamp)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if strings.Contains(direct, "amber") {
+			t.Errorf("%s: a Lamp marshaled directly to %s", s.Name, direct)
+		}
+		got := NewLamp("", "")
+		if err := UnmarshalState(zson.NewUnmarshaler(), zs, got); err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if got.Color() != "amber" || got.Name() != "desk" {
+			t.Errorf("%s: unmarshaled %s", s.Name, Describe(got))
+		}
+	}
+	// An Encoder marshals the state too.
+	var b bytes.Buffer
+	if err := NewEncoder(&b, zson.StyleSimple).Encode(NewLamp("amber", "desk")); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{Color:"amber",Name:"desk"}(=LampState)` + "\n"; b.String() != want {
+		t.Errorf("Encoder wrote %q, want %q", b.String(), want)
+	}
+	if err := NewLamp("", "").SetState(Gem{}); err == nil {
+		t.Error("SetState accepted a Gem")
+	}
+}
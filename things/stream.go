@@ -0,0 +1,197 @@
+package things
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zson"
+)
+
+// An Encoder writes a stream of Things as ZSON, one value per line.
+type Encoder struct {
+	w            io.Writer
+	newMarshaler MarshalerFunc
+	n            int
+	every        int
+	progress     func(processed int)
+}
+
+// NewEncoder returns an Encoder that writes to w using compact
+// marshalers decorated with style.
+func NewEncoder(w io.Writer, style zson.TypeStyle) *Encoder {
+	return NewEncoderWith(w, func() *zson.MarshalContext {
+		m := zson.NewMarshaler()
+		m.Decorate(style)
+		return m
+	})
+}
+
+// NewEncoderWith returns an Encoder that writes to w, marshaling each
+// Thing with a new marshaler from newMarshaler, which may configure it
+// with indentation or named bindings.  Each value in the stream
+// therefore carries its own type definitions and can be read on its own.
+func NewEncoderWith(w io.Writer, newMarshaler MarshalerFunc) *Encoder {
+	return &Encoder{w: w, newMarshaler: newMarshaler}
+}
+
+// SetProgress arranges for fn to be called with the number of Things
+// encoded so far after every every Things.  A nil fn or an every less
+// than one turns progress reporting off.
+func (e *Encoder) SetProgress(every int, fn func(processed int)) {
+	e.every = every
+	e.progress = fn
+}
+
+// Encode writes t followed by a newline.  A nil Thing is an error.
+func (e *Encoder) Encode(t Thing) error {
+	if isNil(t) {
+		return errors.New("cannot encode a nil Thing")
+	}
+	s, err := MarshalThing(e.newMarshaler(), t)
+	if err != nil {
+		return err
+	}
+	if err := WriteValue(e.w, s); err != nil {
+		return err
+	}
+	e.n++
+	if e.progress != nil && e.every > 0 && e.n%e.every == 0 {
+		e.progress(e.n)
+	}
+	return nil
+}
+
+// A Decoder reads a stream of decorated Things.
+type Decoder struct {
+	reader *ValueReader
+	u      *zson.UnmarshalContext
+	err    error
+	last   string
+}
+
+// NewDecoder returns a Decoder that reads from r with every registered
+// type bound.
+func NewDecoder(r io.Reader) *Decoder {
+	u, err := newUnmarshaler()
+	d := NewDecoderWith(r, u)
+	d.err = err
+	return d
+}
+
+// NewDecoderWith returns a Decoder that reads from r using u.
+func NewDecoderWith(r io.Reader, u *zson.UnmarshalContext) *Decoder {
+	return &Decoder{
+		reader: NewValueReader(r, zed.NewContext()),
+		u:      u,
+	}
+}
+
+// Decode returns the next Thing in the stream or io.EOF at its end.  A
+// value that cannot be unmarshaled into a Thing is skipped, so a later
+// call returns the next value, but an error reading the stream itself is
+// sticky and is returned by every later call and by Err.
+func (d *Decoder) Decode() (Thing, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	val, err := d.reader.Read()
+	if err != nil {
+		d.err = err
+		return nil, err
+	}
+	if val == nil {
+		return nil, io.EOF
+	}
+	s, err := zson.FormatValue(val)
+	if err != nil {
+		return nil, err
+	}
+	d.last = s
+	var thing Thing
+	if err := UnmarshalValue(d.u, val, &thing); err != nil {
+		return nil, err
+	}
+	return thing, nil
+}
+
+// Value returns the value most recently read by Decode, formatted as
+// compact ZSON, even if it could not be unmarshaled.
+func (d *Decoder) Value() string {
+	return d.last
+}
+
+// Position returns the position in the stream of the value most
+// recently read by Decode, or of the one it failed to read.
+func (d *Decoder) Position() Position {
+	return d.reader.Position()
+}
+
+// Err returns the sticky error that stopped the stream, if any.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// DecodeEach decodes each Thing in the stream read from r, with every
+// registered type bound, and calls fn with it in order.  It stops at the
+// first value that cannot be decoded or the first error from fn and
+// returns that error.
+func DecodeEach(r io.Reader, fn func(Thing) error) error {
+	dec := NewDecoder(r)
+	for k := 0; ; k++ {
+		thing, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("value %d: %w", k, err)
+		}
+		if err := fn(thing); err != nil {
+			return err
+		}
+	}
+}
+
+// DecodeAll returns all of the Things in the stream read from r.
+func DecodeAll(r io.Reader) ([]Thing, error) {
+	var things []Thing
+	err := DecodeEach(r, func(t Thing) error {
+		things = append(things, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return things, nil
+}
+
+// WriteValue writes the marshaled value s to w terminated by exactly one
+// newline.  Every path that writes ZSON to a stream goes through
+// WriteValue so that compact streams always hold one value per line and
+// concatenated output remains a valid stream.
+func WriteValue(w io.Writer, s string) error {
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.WriteString(strings.TrimRight(s, "\n"))
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	if buf.Cap() <= maxPooledBuffer {
+		bufPool.Put(buf)
+	}
+	return err
+}
+
+// bufPool holds the buffers WriteValue uses to assemble each line, so
+// that encoding a long stream does not allocate a line per value.  A
+// buffer grown past maxPooledBuffer by a large value, such as the list
+// written by bulk -wrap-list, is dropped rather than pooled, so that it
+// is not kept alive to hold the short lines of later values.
+var bufPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+const maxPooledBuffer = 64 << 10
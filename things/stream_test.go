@@ -0,0 +1,299 @@
+package things
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	names := []string{"rose", "flamingo", "rose", "quartz", "sunrise"}
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, zson.StyleSimple)
+	for _, name := range names {
+		if err := enc.Encode(MustMake(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(names) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(names), buf.String())
+	}
+	if lines[0] != lines[2] {
+		t.Errorf("the same Thing encoded differently:\n%s\n%s", lines[0], lines[2])
+	}
+	for k, line := range lines {
+		// Each value stands on its own.
+		thing, err := ZSONToThing(line)
+		if err != nil {
+			t.Fatalf("line %d: %s", k, err)
+		}
+		if thing.Name() != names[k] {
+			t.Errorf("line %d: got %s, want %s", k, thing.Name(), names[k])
+		}
+	}
+	decoded, err := DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(names) {
+		t.Fatalf("decoded %d Things, want %d", len(decoded), len(names))
+	}
+	for k, thing := range decoded {
+		if want := MustMake(names[k]); thing.Name() != want.Name() || thing.Color() != want.Color() {
+			t.Errorf("Thing %d: got %s, want %s", k, Describe(thing), Describe(want))
+		}
+	}
+}
+
+func TestDecodeSameFields(t *testing.T) {
+	// A Plant and an Animal have the same fields, so zson parses the
+	// Contents of this Garden with a type it cannot format and parse
+	// again, and the Decoder must unmarshal the value as it read it.
+	want := &Garden{MyName: "bed", Contents: List{MustMake("rose"), MustMake("flamingo"), MustMake("ivy")}}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, zson.StyleSimple).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, buf.String())
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("decoded %d Things, want 1", len(decoded))
+	}
+	if equal, err := Equal(decoded[0], want); err != nil || !equal {
+		t.Errorf("decoded %s, want %s", Describe(decoded[0]), Describe(want))
+	}
+}
+
+func TestEncodeNil(t *testing.T) {
+	enc := NewEncoder(&bytes.Buffer{}, zson.StyleSimple)
+	if err := enc.Encode(nil); err == nil {
+		t.Error("encoding a nil Thing succeeded")
+	}
+}
+
+// writeNaive writes s as WriteValue does, but with a new line buffer for
+// every value instead of one from bufPool.
+func writeNaive(w io.Writer, s string) error {
+	_, err := w.Write([]byte(strings.TrimRight(s, "\n") + "\n"))
+	return err
+}
+
+// encodeNaive marshals things as an Encoder with style does, writing each
+// with writeNaive.
+func encodeNaive(w io.Writer, style zson.TypeStyle, things []Thing) error {
+	for _, thing := range things {
+		m := zson.NewMarshaler()
+		m.Decorate(style)
+		s, err := MarshalThing(m, thing)
+		if err != nil {
+			return err
+		}
+		if err := writeNaive(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEncodePooled(t *testing.T) {
+	var things []Thing
+	for _, name := range Names() {
+		things = append(things, MustMake(name))
+	}
+	things = append(things, mixedThings(1000)...)
+	for _, s := range Styles() {
+		var pooled, naive bytes.Buffer
+		enc := NewEncoder(&pooled, s.Style)
+		for _, thing := range things {
+			if err := enc.Encode(thing); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := encodeNaive(&naive, s.Style, things); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pooled.Bytes(), naive.Bytes()) {
+			t.Errorf("%s: pooled and naive output differ:\n%s\n%s", s.Name, pooled.String(), naive.String())
+		}
+	}
+}
+
+func BenchmarkEncodePooled(b *testing.B) {
+	things := mixedThings(1000)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		enc := NewEncoder(io.Discard, zson.StyleSimple)
+		for _, thing := range things {
+			if err := enc.Encode(thing); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkEncodeNaive(b *testing.B) {
+	things := mixedThings(1000)
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		if err := encodeNaive(io.Discard, zson.StyleSimple, things); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestWriteValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "\n"},
+		{"{a:1}", "{a:1}\n"},
+		{"{a:1}\n", "{a:1}\n"},
+		{"{a:1}\n\n", "{a:1}\n"},
+		{"{\n    a: 1\n}", "{\n    a: 1\n}\n"},
+		// A buffer too large to pool.
+		{strings.Repeat("a", maxPooledBuffer), strings.Repeat("a", maxPooledBuffer) + "\n"},
+	}
+	for _, tc := range tests {
+		var b bytes.Buffer
+		if err := WriteValue(&b, tc.value); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tc.want {
+			t.Errorf("%q: wrote %q, want %q", tc.value, b.String(), tc.want)
+		}
+	}
+	// Values written one after another form a stream with one value
+	// per line.
+	var b bytes.Buffer
+	for _, s := range []string{"{a:1}\n", "{b:2}", "{c:3}\n"} {
+		if err := WriteValue(&b, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "{a:1}\n{b:2}\n{c:3}\n"; b.String() != want {
+		t.Errorf("stream: wrote %q, want %q", b.String(), want)
+	}
+}
+
+func TestEncodeNewlines(t *testing.T) {
+	for _, n := range []int{1, 3} {
+		var b bytes.Buffer
+		enc := NewEncoder(&b, zson.StyleSimple)
+		for _, thing := range mixedThings(n) {
+			if err := enc.Encode(thing); err != nil {
+				t.Fatal(err)
+			}
+		}
+		s := b.String()
+		if strings.Contains(s, "\n\n") || !strings.HasSuffix(s, "\n") || strings.Count(s, "\n") != n {
+			t.Errorf("%d Things: encoded %q, want one value per line", n, s)
+		}
+	}
+}
+
+func TestEncodeProgress(t *testing.T) {
+	tests := []struct {
+		count, every int
+		nilFn        bool
+		want         []int
+	}{
+		{10, 3, false, []int{3, 6, 9}},
+		{9, 3, false, []int{3, 6, 9}},
+		{2, 3, false, nil},
+		{4, 1, false, []int{1, 2, 3, 4}},
+		{5, 0, false, nil},
+		{5, -1, false, nil},
+		{5, 1, true, nil},
+	}
+	for _, tc := range tests {
+		enc := NewEncoder(io.Discard, zson.StyleSimple)
+		var got []int
+		fn := func(processed int) { got = append(got, processed) }
+		if tc.nilFn {
+			fn = nil
+		}
+		enc.SetProgress(tc.every, fn)
+		for _, thing := range mixedThings(tc.count) {
+			if err := enc.Encode(thing); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%d Things every %d: called with %v, want %v", tc.count, tc.every, got, tc.want)
+			continue
+		}
+		for k := range got {
+			if got[k] != tc.want[k] {
+				t.Errorf("%d Things every %d: called with %v, want %v", tc.count, tc.every, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDecodeEach(t *testing.T) {
+	names := []string{"flamingo", "rose", "quartz", "ivy", "emerald"}
+	var b bytes.Buffer
+	enc := NewEncoder(&b, zson.StyleSimple)
+	for _, name := range names {
+		if err := enc.Encode(MustMake(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := b.String()
+	stop := errors.New("stop")
+	tests := []struct {
+		name   string
+		input  string
+		stopAt int
+		seen   int
+		err    error
+	}{
+		{"every value", input, -1, len(names), nil},
+		{"empty", "", -1, 0, nil},
+		{"fn error", input, 2, 3, stop},
+		{"bad value", input + "junk\n", -1, len(names), ErrBadInput},
+	}
+	for _, tc := range tests {
+		var seen []Thing
+		err := DecodeEach(strings.NewReader(tc.input), func(thing Thing) error {
+			seen = append(seen, thing)
+			if len(seen)-1 == tc.stopAt {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: got error %v, want %v", tc.name, err, tc.err)
+		}
+		if len(seen) != tc.seen {
+			t.Fatalf("%s: fn saw %d Things, want %d", tc.name, len(seen), tc.seen)
+		}
+		for k, thing := range seen {
+			if thing.Name() != names[k] {
+				t.Errorf("%s: Thing %d is %s, want %s", tc.name, k, thing.Name(), names[k])
+			}
+		}
+		all, err := DecodeAll(strings.NewReader(tc.input))
+		if tc.err == ErrBadInput {
+			if !errors.Is(err, ErrBadInput) || all != nil {
+				t.Errorf("%s: DecodeAll returned %d Things and %v, want a bad input error", tc.name, len(all), err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if want := strings.Count(tc.input, "\n"); len(all) != want {
+			t.Errorf("%s: DecodeAll returned %d Things, want %d", tc.name, len(all), want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package things
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zson"
+)
+
+// fieldName returns the name zson uses for the struct field f, which
+// is taken from its zed tag, or failing that its json tag.
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("zed")
+	if tag == "" {
+		tag = f.Tag.Get("json")
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// CheckFields returns an error if the ZSON record s has a field that is
+// not an exported field of the struct underlying v.  zson silently drops
+// such fields on unmarshal, so CheckFields provides a strict mode for
+// untrusted input.  Both an unexpected field and unparsable input are
+// reported with errors wrapping ErrBadInput.
+func CheckFields(s string, v interface{}) error {
+	zv, err := zson.ParseValue(zed.NewContext(), s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	return CheckValueFields(zv, v)
+}
+
+// CheckValueFields is like CheckFields but checks a value that has
+// already been parsed, as UnmarshalValue unmarshals one.
+func CheckValueFields(zv *zed.Value, v interface{}) error {
+	rec := zed.TypeRecordOf(zv.Type)
+	if rec == nil {
+		return nil
+	}
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot check fields of %T", v)
+	}
+	known := make(map[string]bool)
+	for k := 0; k < typ.NumField(); k++ {
+		if f := typ.Field(k); f.PkgPath == "" {
+			known[fieldName(f)] = true
+		}
+	}
+	for _, col := range rec.Columns {
+		if !known[col.Name] {
+			return fmt.Errorf("%w: unexpected field %q for %s", ErrBadInput, col.Name, typ.Name())
+		}
+	}
+	return nil
+}
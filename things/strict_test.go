@@ -0,0 +1,43 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckFields(t *testing.T) {
+	type tagged struct {
+		Color string `zed:"color"`
+		Name  string `json:"name,omitempty"`
+	}
+	tests := []struct {
+		name  string
+		zson  string
+		v     interface{}
+		field string
+	}{
+		{"known fields", `{MyColor:"white",MyName:"quartz",Hardness:7}`, &Mineral{}, ""},
+		{"fewer fields", `{MyColor:"white"}`, &Mineral{}, ""},
+		{"extra field", `{MyColor:"white",MyWeight:3}`, &Mineral{}, "MyWeight"},
+		{"embedded struct", `{BaseThing:{color:"red"},MyName:"rose"}`, &Plant{}, ""},
+		{"tags", `{color:"red",name:"x"}`, &tagged{}, ""},
+		{"tagged field by its Go name", `{Color:"red"}`, &tagged{}, "Color"},
+		{"not a record", `1`, &Mineral{}, ""},
+	}
+	for _, tc := range tests {
+		err := CheckFields(tc.zson, tc.v)
+		if tc.field == "" {
+			if err != nil {
+				t.Errorf("%s: %s", tc.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrBadInput) || !strings.Contains(err.Error(), `unexpected field "`+tc.field+`"`) {
+			t.Errorf("%s: got %v, want an unexpected field error naming %s", tc.name, err, tc.field)
+		}
+	}
+	if err := CheckFields("{a:", &Mineral{}); !errors.Is(err, ErrBadInput) {
+		t.Errorf("unparsable input: got %v, want a bad input error", err)
+	}
+}
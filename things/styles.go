@@ -0,0 +1,19 @@
+package things
+
+import "github.com/brimdata/zed/zson"
+
+// A NamedStyle pairs a zson decoration style with the name by which
+// users select it.
+type NamedStyle struct {
+	Name  string
+	Style zson.TypeStyle
+}
+
+// Styles returns the decoration styles in order of increasing detail.
+func Styles() []NamedStyle {
+	return []NamedStyle{
+		{"none", zson.StyleNone},
+		{"simple", zson.StyleSimple},
+		{"package", zson.StylePackage},
+	}
+}
@@ -0,0 +1,32 @@
+package things
+
+import (
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestStyles(t *testing.T) {
+	want := []NamedStyle{
+		{"none", zson.StyleNone},
+		{"simple", zson.StyleSimple},
+		{"package", zson.StylePackage},
+	}
+	got := Styles()
+	if len(got) != len(want) {
+		t.Fatalf("got %d styles, want %d: %v", len(got), len(want), got)
+	}
+	for k := range want {
+		if got[k] != want[k] {
+			t.Errorf("style %d is %v, want %v", k, got[k], want[k])
+		}
+	}
+	// The order is of increasing detail, so each style's decorated
+	// type name is at least as long as the one before.
+	rose := MustMake("rose")
+	for k := 1; k < len(got); k++ {
+		if prev, cur := TypeName(rose, got[k-1].Style), TypeName(rose, got[k].Style); len(cur) < len(prev) {
+			t.Errorf("%s names a Plant %q, shorter than %s's %q", got[k].Name, cur, got[k-1].Name, prev)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package things
+
+// A Swatch is a Thing whose color may be unknown, in which case MyColor is
+// nil and is marshaled as a null.  Color returns "" for a Swatch of
+// unknown color, but only NullAsEmpty makes its MyColor point to "".
+type Swatch struct {
+	MyColor *string
+	MyName  string
+}
+
+func (s *Swatch) Color() string {
+	if s.MyColor == nil {
+		return ""
+	}
+	return *s.MyColor
+}
+
+func (s *Swatch) Name() string      { return s.MyName }
+func (s *Swatch) setColor(c string) { s.MyColor = &c }
+
+func init() {
+	Register("swatch", func() Thing { return &Swatch{MyName: "swatch"} })
+}
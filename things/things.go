@@ -0,0 +1,38 @@
+package things
+
+type Thing interface {
+	Color() string
+	Name() string
+}
+
+// BaseThing holds the fields common to Plant and Animal.  Unlike
+// encoding/json, zson does not promote the fields of an embedded struct,
+// so they are marshaled as a nested record under the BaseThing field,
+// e.g., {BaseThing:{color:"red"},MyName:"rose"}, and unmarshaled back
+// into the embedded struct the same way.
+type BaseThing struct {
+	MyColor string `zed:"color"`
+}
+
+func (b *BaseThing) Color() string     { return b.MyColor }
+func (b *BaseThing) setColor(c string) { b.MyColor = c }
+
+type Plant struct {
+	BaseThing
+	MyName string
+}
+
+func (p *Plant) Name() string { return p.MyName }
+
+type Animal struct {
+	BaseThing
+	MyName string
+}
+
+func (a *Animal) Name() string { return a.MyName }
+
+func init() {
+	Register("rose", func() Thing { return &Plant{BaseThing{"red"}, "rose"} })
+	Register("ivy", func() Thing { return &Plant{BaseThing{"green"}, "ivy"} })
+	Register("flamingo", func() Thing { return &Animal{BaseThing{"pink"}, "flamingo"} })
+}
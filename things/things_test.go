@@ -0,0 +1,120 @@
+package things
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// mixedThings returns n Things alternating among rose, ivy, and flamingo.
+func mixedThings(n int) []Thing {
+	names := []string{"rose", "ivy", "flamingo"}
+	things := make([]Thing, n)
+	for k := range things {
+		things[k] = MustMake(names[k%len(names)])
+	}
+	return things
+}
+
+func BenchmarkMarshalThings(b *testing.B) {
+	things := mixedThings(10000)
+	var size int64
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		// A marshaler defines each named type only the first time it
+		// formats it, so each value needs a marshaler of its own.
+		m := zson.NewMarshaler()
+		m.Decorate(zson.StyleSimple)
+		s, err := MarshalThings(m, things)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = int64(len(s))
+	}
+	b.SetBytes(size)
+}
+
+func BenchmarkUnmarshalThings(b *testing.B) {
+	m := zson.NewMarshaler()
+	m.Decorate(zson.StyleSimple)
+	s, err := MarshalThings(m, mixedThings(10000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Bindings are all an unmarshaler holds, so one serves every
+	// iteration.
+	u, err := newUnmarshaler()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(s)))
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		things, err := UnmarshalThings(u, s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(things) != 10000 {
+			b.Fatalf("unmarshaled %d Things", len(things))
+		}
+	}
+}
+
+func TestFieldTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		thing  Thing
+		tagged string
+		field  string
+	}{
+		{"Plant", MustMake("rose"), "color:", "MyColor:"},
+		{"Animal", MustMake("flamingo"), "color:", "MyColor:"},
+		{"Garden", &Garden{MyName: "bed"}, "Name:", "MyName:"},
+	}
+	for _, tc := range tests {
+		s, err := ThingToZSON(tc.thing, zson.StyleSimple)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if !strings.Contains(s, tc.tagged) || strings.Contains(s, tc.field) {
+			t.Errorf("%s: %s has the field %s rather than its tag %s", tc.name, s, tc.field, tc.tagged)
+		}
+		got, err := ZSONToThing(s)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if got.Color() != tc.thing.Color() || got.Name() != tc.thing.Name() {
+			t.Errorf("%s: unmarshaled %s, want %s", tc.name, Describe(got), Describe(tc.thing))
+		}
+	}
+}
+
+func TestBaseThing(t *testing.T) {
+	tests := []struct {
+		name  string
+		thing Thing
+		want  string
+	}{
+		{"Plant", MustMake("ivy"), `{BaseThing:{color:"green"}(=BaseThing),MyName:"ivy"}(=Plant)`},
+		{"Animal", MustMake("flamingo"), `{BaseThing:{color:"pink"}(=BaseThing),MyName:"flamingo"}(=Animal)`},
+	}
+	for _, tc := range tests {
+		// zson nests the embedded struct rather than promoting its
+		// fields.
+		s, err := ThingToZSON(tc.thing, zson.StyleSimple)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if s != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, s, tc.want)
+		}
+		got, err := ZSONToThing(s)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if got.Color() != tc.thing.Color() {
+			t.Errorf("%s: unmarshaled color %q, want %q", tc.name, got.Color(), tc.thing.Color())
+		}
+	}
+}
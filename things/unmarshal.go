@@ -0,0 +1,167 @@
+package things
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/brimdata/zed"
+	"github.com/brimdata/zed/zcode"
+	"github.com/brimdata/zed/zson"
+)
+
+var decoratorRE = regexp.MustCompile(`\(=([^()]+)\)$`)
+
+// decoratorName returns the type name of the outermost decorator of the
+// ZSON value s, or the empty string if s is not decorated.
+func decoratorName(s string) string {
+	m := decoratorRE.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// UnmarshalInto unmarshals the ZSON value s into v, which must be a
+// non-nil pointer.  If s, or a value nested within it, is decorated with
+// a type name that has not been bound to u, the error wraps
+// ErrUnboundType and names the missing type instead of reporting zson's
+// generic binding failure, or wraps ErrAmbiguousType if BindAll left the
+// name unbound because two registered types share it.  Any other failure
+// to unmarshal s wraps ErrBadInput.
+func UnmarshalInto(u *zson.UnmarshalContext, s string, v interface{}) (err error) {
+	if err := checkDestination(v); err != nil {
+		return err
+	}
+	defer recoverBadInput(&err, v)
+	zv, err := zson.ParseValue(zed.NewContext(), s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBadInput, err)
+	}
+	if zv == nil {
+		return fmt.Errorf("%w: no ZSON value", ErrBadInput)
+	}
+	return unmarshalValue(u.UnmarshalZNGContext, zv, v)
+}
+
+// UnmarshalValue is like UnmarshalInto but unmarshals a value that has
+// already been parsed, such as one returned by a ValueReader.  zson
+// parses a list holding Things of two types with the same fields, such
+// as a Plant and an Animal, into a value whose type it cannot then format
+// so that it parses again, so a value read from a stream should be
+// unmarshaled as read rather than formatted and passed to UnmarshalInto.
+func UnmarshalValue(u *zson.UnmarshalContext, zv *zed.Value, v interface{}) (err error) {
+	if err := checkDestination(v); err != nil {
+		return err
+	}
+	defer recoverBadInput(&err, v)
+	return unmarshalValue(u.UnmarshalZNGContext, zv, v)
+}
+
+func checkDestination(v interface{}) error {
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("destination must be a non-nil pointer")
+	}
+	return nil
+}
+
+// recoverBadInput, when deferred, turns a panic while unmarshaling into
+// v into an error wrapping ErrBadInput.  zson panics instead of failing
+// on some malformed input, such as an empty backquoted string, and when
+// a value that is not a record, such as 1 or "x"(=Foo), does not fit an
+// interface.
+func recoverBadInput(err *error, v interface{}) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: cannot unmarshal into %s: %v", ErrBadInput, reflect.TypeOf(v).Elem(), r)
+	}
+}
+
+// unmarshalValue unmarshals zv into v and classifies any failure as
+// UnmarshalInto does.  A List unmarshals each of its elements with it.
+func unmarshalValue(u *zson.UnmarshalZNGContext, zv *zed.Value, v interface{}) error {
+	err := u.Unmarshal(zv, v)
+	if err == nil || errors.Is(err, ErrBadInput) || errors.Is(err, ErrUnboundType) || errors.Is(err, ErrAmbiguousType) {
+		return err
+	}
+	if name := unboundName(u, zv.Type, reflect.TypeOf(v)); name != "" {
+		if err := ambiguousType(name); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w %q; call Bind", ErrUnboundType, name)
+	}
+	return fmt.Errorf("%w: %s", ErrBadInput, err)
+}
+
+var unmarshalerType = reflect.TypeOf((*zson.ZNGUnmarshaler)(nil)).Elem()
+
+// unboundName returns the first type name in typ, depth first, that u
+// needs a binding for to unmarshal a value of type typ into a Go value of
+// type goType but has none for, or the empty string if there is no such
+// name.  zson needs a binding wherever a named record is unmarshaled into
+// an interface, as a decorated Thing is into a Thing.
+func unboundName(u *zson.UnmarshalZNGContext, typ zed.Type, goType reflect.Type) string {
+	for goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	if reflect.PtrTo(goType).Implements(unmarshalerType) {
+		// A List unmarshals its elements itself and names any
+		// unbound type among them.
+		return ""
+	}
+	switch goType.Kind() {
+	case reflect.Interface:
+		named, ok := typ.(*zed.TypeNamed)
+		if !ok || zed.TypeRecordOf(named) == nil {
+			return ""
+		}
+		template := boundType(u, named.Name)
+		if template == nil {
+			return named.Name
+		}
+		return unboundName(u, named.Type, template)
+	case reflect.Struct:
+		rec := zed.TypeRecordOf(typ)
+		if rec == nil {
+			return ""
+		}
+		fields := make(map[string]reflect.Type)
+		for k := 0; k < goType.NumField(); k++ {
+			f := goType.Field(k)
+			fields[fieldName(f)] = f.Type
+		}
+		for _, col := range rec.Columns {
+			if fieldType, ok := fields[col.Name]; ok {
+				if name := unboundName(u, col.Type, fieldType); name != "" {
+					return name
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if inner := zed.InnerType(zed.TypeUnder(typ)); inner != nil {
+			return unboundName(u, inner, goType.Elem())
+		}
+	case reflect.Map:
+		if m, ok := zed.TypeUnder(typ).(*zed.TypeMap); ok {
+			return unboundName(u, m.ValType, goType.Elem())
+		}
+	}
+	return ""
+}
+
+// boundType returns the Go type that u binds to the type name, or nil if
+// it binds none.  zson cannot be asked for its bindings, so boundType
+// unmarshals an empty record of that name and sees what it becomes.
+func boundType(u *zson.UnmarshalZNGContext, name string) reflect.Type {
+	zctx := zed.NewContext()
+	named, err := zctx.LookupTypeNamed(name, zctx.MustLookupTypeRecord(nil))
+	if err != nil {
+		return nil
+	}
+	var v interface{}
+	if err := u.Unmarshal(zed.NewValue(named, zcode.Bytes{}), &v); err != nil || v == nil {
+		return nil
+	}
+	return reflect.TypeOf(v)
+}
@@ -0,0 +1,103 @@
+package things
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+)
+
+func TestUnboundType(t *testing.T) {
+	type holder struct {
+		Thing Thing
+	}
+	tests := []struct {
+		name    string
+		value   interface{}
+		dest    func() interface{}
+		unbound string
+	}{
+		{"top level", MustMake("quartz"), func() interface{} { return new(Thing) }, "Mineral"},
+		{"bound", MustMake("rose"), func() interface{} { return new(Thing) }, ""},
+		{"unbound Garden", &Garden{MyName: "g"}, func() interface{} { return new(Thing) }, "Garden"},
+		{"within a Garden", &Garden{MyName: "g", Contents: List{MustMake("rose"), MustMake("quartz")}}, func() interface{} { return new(Garden) }, "Mineral"},
+		{"within a field", holder{MustMake("quartz")}, func() interface{} { return new(holder) }, "Mineral"},
+		{"within a slice", []holder{{MustMake("quartz")}}, func() interface{} { return new([]holder) }, "Mineral"},
+	}
+	for _, tc := range tests {
+		m := zson.NewMarshaler()
+		m.Decorate(zson.StyleSimple)
+		s, err := m.Marshal(tc.value)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		u := zson.NewUnmarshaler()
+		if err := u.Bind(Plant{}, Animal{}); err != nil {
+			t.Fatal(err)
+		}
+		err = UnmarshalInto(u, s, tc.dest())
+		if tc.unbound == "" {
+			if err != nil {
+				t.Errorf("%s: %s", tc.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrUnboundType) || !strings.Contains(err.Error(), `"`+tc.unbound+`"`) {
+			t.Errorf("%s: got %v, want an unbound type error naming %s", tc.name, err, tc.unbound)
+		}
+	}
+}
+
+func TestBadInput(t *testing.T) {
+	u, err := newUnmarshaler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"", "hello", "{a:", `{MyColor:1,MyName:"x"}(=Gem)`} {
+		var thing Thing
+		if err := UnmarshalInto(u, s, &thing); !errors.Is(err, ErrBadInput) {
+			t.Errorf("%q: got %v, want a bad input error", s, err)
+		}
+	}
+}
+
+func TestUnmarshalIntoDestination(t *testing.T) {
+	u, err := newUnmarshaler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ThingToZSON(MustMake("rose"), zson.StyleSimple)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var thing Thing
+	var plant Plant
+	tests := []struct {
+		name string
+		dest interface{}
+		ok   bool
+	}{
+		{"nil", nil, false},
+		{"value", plant, false},
+		{"nil pointer", (*Plant)(nil), false},
+		{"nil Thing pointer", (*Thing)(nil), false},
+		{"pointer", &plant, true},
+		{"Thing pointer", &thing, true},
+	}
+	for _, tc := range tests {
+		err := UnmarshalInto(u, s, tc.dest)
+		if tc.ok {
+			if err != nil {
+				t.Errorf("%s: %s", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "non-nil pointer") {
+			t.Errorf("%s: got %v, want a non-nil pointer error", tc.name, err)
+		}
+	}
+	if plant.Color() != "red" || thing.Color() != "red" {
+		t.Errorf("unmarshaled colors %q and %q, want red", plant.Color(), thing.Color())
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/brimdata/zed/pkg/nano"
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// togo writes a Go composite literal for the named Thing, as decoded
+// from its ZSON, for use as a test fixture, e.g.,
+// &things.Plant{BaseThing: things.BaseThing{MyColor: "red"}, MyName: "rose"}.
+func togo(w io.Writer, style zson.TypeStyle, name string) error {
+	thing, err := things.Make(name)
+	if err != nil {
+		return err
+	}
+	s, err := marshal(style, thing)
+	if err != nil {
+		return err
+	}
+	var decoded things.Thing
+	if err := things.UnmarshalInto(newUnmarshaler(), s, &decoded); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, goLiteral(reflect.ValueOf(decoded)))
+	return err
+}
+
+var tsType = reflect.TypeOf(nano.Ts(0))
+
+// goLiteral renders v as Go source, omitting zero-valued struct fields.
+// Values it has no literal form for, such as structs with unexported
+// fields, are rendered with %#v.
+func goLiteral(v reflect.Value) string {
+	if v.IsValid() && v.Type() == tsType {
+		t := v.Interface().(nano.Ts).Time()
+		return fmt.Sprintf("nano.Date(%d, %d, %d, %d, %d, %d, %d)",
+			t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "nil"
+		}
+		if v.Elem().Kind() == reflect.Struct && exportedOnly(v.Elem().Type()) {
+			return "&" + goLiteral(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return goLiteral(v.Elem())
+	case reflect.Struct:
+		if !exportedOnly(v.Type()) {
+			break
+		}
+		var fields []string
+		for k := 0; k < v.NumField(); k++ {
+			if f := v.Field(k); !f.IsZero() {
+				fields = append(fields, v.Type().Field(k).Name+": "+goLiteral(f))
+			}
+		}
+		return v.Type().String() + "{" + strings.Join(fields, ", ") + "}"
+	case reflect.Slice:
+		if v.IsNil() {
+			return "nil"
+		}
+		elems := make([]string, v.Len())
+		for k := range elems {
+			elems[k] = goLiteral(v.Index(k))
+		}
+		return v.Type().String() + "{" + strings.Join(elems, ", ") + "}"
+	case reflect.String:
+		s := strconv.Quote(v.String())
+		if v.Type().PkgPath() != "" {
+			return v.Type().String() + "(" + s + ")"
+		}
+		return s
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if v.Type().PkgPath() != "" {
+			return fmt.Sprintf("%s(%v)", v.Type(), v)
+		}
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+func exportedOnly(typ reflect.Type) bool {
+	for k := 0; k < typ.NumField(); k++ {
+		if typ.Field(k).PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
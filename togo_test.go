@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"reflect"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestToGo(t *testing.T) {
+	tests := map[string]string{
+		"diamond":  `&things.Mineral{MyColor: "clear", MyName: "diamond", Hardness: 10}`,
+		"emerald":  `&things.Gem{MyColor: "green", MyName: "emerald"}`,
+		"flamingo": `&things.Animal{BaseThing: things.BaseThing{MyColor: "pink"}, MyName: "flamingo"}`,
+		"ivy":      `&things.Plant{BaseThing: things.BaseThing{MyColor: "green"}, MyName: "ivy"}`,
+		"quartz":   `&things.Mineral{MyColor: "white", MyName: "quartz", Hardness: 7}`,
+		"robot":    `&things.Machine{MyColor: "silver", MyName: "robot", LastErr: things.Error("boom")}`,
+		"rose":     `&things.Plant{BaseThing: things.BaseThing{MyColor: "red"}, MyName: "rose"}`,
+		"sapphire": `&things.Gem{MyColor: "blue", MyName: "sapphire"}`,
+		"sunrise":  `&things.Event{MyColor: "orange", MyName: "sunrise", When: nano.Date(2021, 6, 21, 4, 43, 10, 123456789)}`,
+		"swatch":   `&things.Swatch{MyName: "swatch"}`,
+	}
+	for _, name := range things.Names() {
+		want, ok := tests[name]
+		if !ok {
+			t.Errorf("no literal pinned for %s", name)
+			continue
+		}
+		for _, s := range things.Styles() {
+			if s.Style == zson.StyleNone {
+				continue
+			}
+			var b bytes.Buffer
+			if err := togo(&b, s.Style, name); err != nil {
+				t.Fatalf("%s, %s: %s", name, s.Name, err)
+			}
+			if got := b.String(); got != want+"\n" {
+				t.Errorf("%s, %s: got %s, want %s", name, s.Name, got, want)
+			}
+		}
+		if _, err := parser.ParseExpr(want); err != nil {
+			t.Errorf("%s: %s does not parse: %s", name, want, err)
+		}
+	}
+	// The literals compile to the Things they were printed from.
+	for name, lit := range map[string]things.Thing{
+		"rose":   &things.Plant{BaseThing: things.BaseThing{MyColor: "red"}, MyName: "rose"},
+		"quartz": &things.Mineral{MyColor: "white", MyName: "quartz", Hardness: 7},
+		"robot":  &things.Machine{MyColor: "silver", MyName: "robot", LastErr: things.Error("boom")},
+	} {
+		if !reflect.DeepEqual(lit, things.MustMake(name)) {
+			t.Errorf("%s: literal %s differs from the Thing", name, things.Describe(lit))
+		}
+	}
+}
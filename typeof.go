@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// typeOf writes the Zed type of the named Thing as marshaled with the
+// given style.
+func typeOf(w io.Writer, style zson.TypeStyle, name string) error {
+	thing, err := things.Make(name)
+	if err != nil {
+		return err
+	}
+	zv, err := newMarshaler(style).MarshalZNGContext.Marshal(thing)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, zson.FormatType(zv.Type))
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		style zson.TypeStyle
+		want  string
+	}{
+		{zson.StyleNone, "{BaseThing:{color:string},MyName:string}\n"},
+		{zson.StyleSimple, "Plant={BaseThing:BaseThing={color:string},MyName:string}\n"},
+		{zson.StylePackage, "things.Plant={BaseThing:things.BaseThing={color:string},MyName:string}\n"},
+	}
+	for _, tc := range tests {
+		var b bytes.Buffer
+		if err := typeOf(&b, tc.style, "rose"); err != nil {
+			t.Fatalf("style %d: %s", tc.style, err)
+		}
+		if b.String() != tc.want {
+			t.Errorf("style %d: got %q, want %q", tc.style, b.String(), tc.want)
+		}
+	}
+	if err := typeOf(&bytes.Buffer{}, zson.StyleSimple, "unicorn"); !errors.Is(err, things.ErrUnknownThing) {
+		t.Errorf("unicorn: got %v, want an unknown thing error", err)
+	}
+}
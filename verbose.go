@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mccanne/zmarshal/things"
+)
+
+// verbose enables diagnostic logging to stderr; see the -v flag.
+var verbose bool
+
+// quiet suppresses all output but errors and, with -v, diagnostics; see
+// the -quiet flag.
+var quiet bool
+
+// info receives informational messages such as statistics and progress,
+// which go to stderr unless -quiet is given.
+var info io.Writer = os.Stderr
+
+func verbosef(format string, args ...interface{}) {
+	if verbose {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+func logBindings() {
+	verbosef("bound types: %s\n", strings.Join(things.TypeNames(), ", "))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStderr returns what fn writes to os.Stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = w
+	done := make(chan string)
+	go func() {
+		var b bytes.Buffer
+		io.Copy(&b, r)
+		done <- b.String()
+	}()
+	fn()
+	os.Stderr = saved
+	w.Close()
+	return <-done
+}
+
+func TestVerboseBindings(t *testing.T) {
+	info = &bytes.Buffer{}
+	defer func() { verbose = false }()
+	in := `{BaseThing:{color:"red"}(=BaseThing),MyName:"rose"}(=Plant)` + "\n"
+	tests := []struct {
+		verbose bool
+		want    []string
+	}{
+		{false, nil},
+		{true, []string{"bound types: ", "Plant", "Animal", "decoded *things.Plant\n", "read 60 bytes\n"}},
+	}
+	for _, tc := range tests {
+		verbose = tc.verbose
+		var out bytes.Buffer
+		var err error
+		stderr := captureStderr(t, func() {
+			err = decode(&out, strings.NewReader(in), nil)
+		})
+		if err != nil {
+			t.Fatalf("verbose %t: %s", tc.verbose, err)
+		}
+		if want := "Plant(red) named \"rose\"\n"; out.String() != want {
+			t.Errorf("verbose %t: output %q, want %q", tc.verbose, out.String(), want)
+		}
+		if tc.want == nil && stderr != "" {
+			t.Errorf("verbose %t: logged %q", tc.verbose, stderr)
+		}
+		for _, s := range tc.want {
+			if !strings.Contains(stderr, s) {
+				t.Errorf("verbose %t: log does not contain %q:\n%s", tc.verbose, s, stderr)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// verify checks that marshal, unmarshal, and re-marshal of the named
+// Thing produces byte-identical ZSON.  With -assert-type, which may
+// follow the name, it also checks that the unmarshaled Thing has the
+// named concrete type.  The check compares compact encodings; as with
+// diff, -pretty makes any difference show up field by field.
+func verify(w io.Writer, style zson.TypeStyle, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	assertType := fs.String("assert-type", "", "require the unmarshaled Thing to have this type")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		usage()
+	}
+	name := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		usage()
+	}
+	isType, err := typeFilter(*assertType)
+	if err != nil {
+		return err
+	}
+	thing, err := things.Make(name)
+	if err != nil {
+		return err
+	}
+	before, err := newMarshalerIndent(style, 0).Marshal(thing)
+	if err != nil {
+		return err
+	}
+	var decoded things.Thing
+	if err := things.UnmarshalInto(newUnmarshaler(), before, &decoded); err != nil {
+		return err
+	}
+	if !isType(decoded) {
+		return fmt.Errorf("%s: unmarshaled as %s, want %s", name, thingType(decoded), *assertType)
+	}
+	after, err := newMarshalerIndent(style, 0).Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	changed, err := prettyDiff(w, "marshaled", "re-marshaled", before, after)
+	if err != nil {
+		return err
+	}
+	if changed {
+		return errors.New("round trip is not stable")
+	}
+	fmt.Fprintf(w, "%s: ok\n", name)
+	return nil
+}
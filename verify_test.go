@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+func TestVerify(t *testing.T) {
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			// Undecorated values cannot be unmarshaled into a Thing.
+			continue
+		}
+		for _, name := range things.Names() {
+			var buf bytes.Buffer
+			if err := verify(&buf, s.Style, []string{name}); err != nil {
+				t.Errorf("%s %s: %s\n%s", s.Name, name, err, buf.String())
+				continue
+			}
+			if want := name + ": ok\n"; buf.String() != want {
+				t.Errorf("%s %s: got %q, want %q", s.Name, name, buf.String(), want)
+			}
+		}
+	}
+}
+
+func TestVerifyAssertType(t *testing.T) {
+	tests := []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"rose", "-assert-type", "Plant"}, true},
+		{[]string{"-assert-type", "Animal", "flamingo"}, true},
+		{[]string{"rose", "-assert-type", "Animal"}, false},
+		{[]string{"rose", "-assert-type", "Nonesuch"}, false},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		err := verify(&buf, zson.StyleSimple, tc.args)
+		if tc.ok && err != nil {
+			t.Errorf("%v: %s", tc.args, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("%v: succeeded, want an error", tc.args)
+		}
+	}
+}
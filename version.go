@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+const zedModule = "github.com/brimdata/zed"
+
+// version writes the zmarshal build version and the version of the zed
+// module it was built against, or "unknown" for either when the binary
+// carries no build information.
+func version(w io.Writer) {
+	self, zed := "unknown", "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v := info.Main.Version; v != "" {
+			self = v
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == zedModule {
+				zed = dep.Version
+				if dep.Replace != nil {
+					zed += " => " + dep.Replace.Path + " " + dep.Replace.Version
+				}
+			}
+		}
+	}
+	fmt.Fprintf(w, "zmarshal %s\n%s %s\n", self, zedModule, zed)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	var b bytes.Buffer
+	version(&b)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	prefixes := []string{"zmarshal ", zedModule + " "}
+	if len(lines) != len(prefixes) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(prefixes), b.String())
+	}
+	for k, prefix := range prefixes {
+		if !strings.HasPrefix(lines[k], prefix) || strings.TrimPrefix(lines[k], prefix) == "" {
+			t.Errorf("line %d is %q, want %q followed by a version", k, lines[k], prefix)
+		}
+	}
+}
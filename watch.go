@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// watchInterval is how often watch checks its file for changes.
+var watchInterval = time.Second
+
+// watchGiveUp is how long watch goes on checking a file it can no longer
+// stat before it fails.
+var watchGiveUp = time.Minute
+
+// clearScreen is the ANSI sequence that clears a terminal and homes the
+// cursor.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// watch decodes the Things in the named file and writes them to w
+// pretty-printed, then does so again each time the file's modification
+// time changes, clearing the screen first when w is a terminal.  Errors
+// in the file are shown in place of its Things so that it can be fixed
+// and saved again.  So is an error checking the file, as when an editor
+// replaces it, and the file is shown again once it can be checked, but
+// watch fails if the file is missing at the start or cannot be checked
+// for watchGiveUp.  An interrupt stops it cleanly.
+func watch(w io.Writer, style zson.TypeStyle, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	newMarshaler := marshalerFuncIndent(style, prettyIndent)
+	var last, failing time.Time
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		fi, err := os.Stat(path)
+		if err != nil {
+			if failing.IsZero() {
+				failing = time.Now()
+				// Show the file again once it is back, whatever
+				// its modification time.
+				last = time.Time{}
+				if isTerminal(w) {
+					io.WriteString(w, clearScreen)
+				}
+				fmt.Fprintf(w, "%s: %s\n", path, err)
+			} else if time.Since(failing) >= watchGiveUp {
+				return fmt.Errorf("watch: could not check %s for %s: %w", path, watchGiveUp, err)
+			}
+		} else {
+			failing = time.Time{}
+			if mtime := fi.ModTime(); !mtime.Equal(last) {
+				last = mtime
+				if isTerminal(w) {
+					io.WriteString(w, clearScreen)
+				}
+				if err := showFile(w, newMarshaler, path); err != nil {
+					fmt.Fprintf(w, "%s: %s\n", path, err)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func showFile(w io.Writer, newMarshaler things.MarshalerFunc, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := things.NewDecoderWith(limitReader(f, defaultMaxBytes), newUnmarshaler())
+	enc := things.NewEncoderWith(w, newMarshaler)
+	for {
+		thing, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			err = enc.Encode(thing)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
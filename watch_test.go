@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brimdata/zed/zson"
+)
+
+// A syncBuffer is a bytes.Buffer safe for one goroutine to write while
+// another reads.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestWatch(t *testing.T) {
+	saved := watchInterval
+	watchInterval = 10 * time.Millisecond
+	defer func() { watchInterval = saved }()
+	path := filepath.Join(t.TempDir(), "in.zson")
+	// write replaces the file and moves its modification time forward,
+	// since the file system may not notice two writes in quick
+	// succession.
+	mtime := time.Now()
+	write := func(text string) {
+		if err := os.WriteFile(path, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+		mtime = mtime.Add(time.Second)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// waitFor waits for the output to have n copies of s.
+	var out syncBuffer
+	waitFor := func(s string, n int) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); strings.Count(out.String(), s) < n; {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %d of %q in\n%s", n, s, out.String())
+			}
+			time.Sleep(watchInterval)
+		}
+	}
+	write(stream(t, zson.StyleSimple, "rose"))
+	done := make(chan error)
+	go func() { done <- watch(&out, zson.StyleSimple, path) }()
+	waitFor(`MyName: "rose"`, 1)
+	write(stream(t, zson.StyleSimple, "flamingo", "ivy"))
+	waitFor(`MyName: "ivy"`, 1)
+	if got := out.String(); strings.Count(got, `MyName: "rose"`) != 1 || !strings.Contains(got, `MyName: "flamingo"`) {
+		t.Errorf("got\n%swant rose once and then flamingo and ivy", got)
+	}
+	// An error in the file is shown in place of its Things.
+	write("junk\n")
+	waitFor(path+": ", 1)
+	// So is an error checking the file, until it is back.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("no such file", 1)
+	write(stream(t, zson.StyleSimple, "rose"))
+	waitFor(`MyName: "rose"`, 2)
+
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(os.Interrupt)
+	}
+	if err != nil {
+		t.Skipf("cannot interrupt: %s", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("interrupted watch returned %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop on an interrupt")
+	}
+}
+
+func TestWatchMissing(t *testing.T) {
+	saved, savedGiveUp := watchInterval, watchGiveUp
+	watchInterval, watchGiveUp = 10*time.Millisecond, 50*time.Millisecond
+	defer func() { watchInterval, watchGiveUp = saved, savedGiveUp }()
+	path := filepath.Join(t.TempDir(), "in.zson")
+	if err := watch(&bytes.Buffer{}, zson.StyleSimple, path); !os.IsNotExist(err) {
+		t.Errorf("watching a missing file: got %v, want a not exist error", err)
+	}
+	// A file that stays missing is given up on.
+	if err := os.WriteFile(path, []byte(stream(t, zson.StyleSimple, "rose")), 0666); err != nil {
+		t.Fatal(err)
+	}
+	var out syncBuffer
+	done := make(chan error)
+	go func() { done <- watch(&out, zson.StyleSimple, path) }()
+	for deadline := time.Now().Add(5 * time.Second); !strings.Contains(out.String(), `MyName: "rose"`); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the rose in\n%s", out.String())
+		}
+		time.Sleep(watchInterval)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), "could not check") {
+			t.Errorf("got %v, want an error giving up on the missing file", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not give up on the missing file")
+	}
+}
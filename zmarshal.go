@@ -1,131 +1,960 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
 )
 
-type Thing interface {
-	Color() string
+func parseStyle(name string) (zson.TypeStyle, error) {
+	for _, s := range things.Styles() {
+		if s.Name == name {
+			return s.Style, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown style %q (valid styles: %s)", name, styleNames())
 }
 
-type Plant struct {
-	MyColor string
+// styleNames returns the names of the styles as a comma-separated list.
+func styleNames() string {
+	var names []string
+	for _, s := range things.Styles() {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, ", ")
 }
 
-func (p *Plant) Color() string { return p.MyColor }
+// outPath is the output file given by -o, if any.
+var outPath string
 
-type Animal struct {
-	MyColor string
+// dryRun discards output, leaving any -o file untouched, and reports
+// how much would have been written; see -dry-run.
+var dryRun bool
+
+// appendOut adds to the -o file instead of truncating it; see -append.
+var appendOut bool
+
+// out is where main directs command output: stdout, or the -o file.
+// Examples write to it, their ZSON values through emit, and run passes it
+// to the commands, so tests can capture output by swapping it.
+var out io.Writer = os.Stdout
+
+// emit writes the ZSON value s to out on a line of its own.
+func emit(s string) error {
+	return things.WriteValue(out, s)
+}
+
+// nameBindings rename every registered type under StyleSimple; see the
+// -prefix and -decorator flags.
+var nameBindings []zson.Binding
+
+// decorators are the custom Decorators selectable with -decorator.
+var decorators = map[string]things.Decorator{
+	"lower": things.Lowercase,
+}
+
+// pretty selects indented, multi-line output; see the -pretty flag.
+var pretty bool
+
+// prettyIndent is the indentation width of pretty output; see -indent.
+var prettyIndent = 4
+
+func newMarshaler(style zson.TypeStyle) *zson.MarshalContext {
+	var indent int
+	if pretty {
+		indent = prettyIndent
+	}
+	return newMarshalerIndent(style, indent)
+}
+
+// newMarshalerIndent is like newMarshaler but ignores -pretty and
+// -indent in favor of indent, where 0 means compact output.
+func newMarshalerIndent(style zson.TypeStyle, indent int) *zson.MarshalContext {
+	m := zson.NewMarshalerIndent(indent)
+	m.Decorate(style)
+	if style == zson.StyleSimple && nameBindings != nil {
+		m.NamedBindings(nameBindings)
+	}
+	return m
+}
+
+// marshal marshals v with a new marshaler.  A marshaler writes the
+// definition of each named type, e.g., (=Plant), only the first time it
+// formats the type and refers to it as (Plant) after that, so reusing
+// one would make every value after the first depend on the ones before
+// it.
+func marshal(style zson.TypeStyle, v interface{}) (string, error) {
+	return newMarshaler(style).Marshal(v)
+}
+
+// marshalerFunc returns a things.MarshalerFunc that makes marshalers
+// like newMarshaler, for writing streams with a things.Encoder.
+func marshalerFunc(style zson.TypeStyle) things.MarshalerFunc {
+	return func() *zson.MarshalContext {
+		return newMarshaler(style)
+	}
+}
+
+// marshalerFuncIndent is like marshalerFunc but makes marshalers like
+// newMarshalerIndent.
+func marshalerFuncIndent(style zson.TypeStyle, indent int) things.MarshalerFunc {
+	return func() *zson.MarshalContext {
+		return newMarshalerIndent(style, indent)
+	}
+}
+
+func newUnmarshaler() *zson.UnmarshalContext {
+	u := zson.NewUnmarshaler()
+	if err := things.BindAll(u); err != nil {
+		fatal(err)
+	}
+	if nameBindings != nil {
+		u.NamedBindings(nameBindings)
+	}
+	return u
+}
+
+func makeThings(names ...string) ([]things.Thing, error) {
+	var out []things.Thing
+	for _, name := range names {
+		thing, err := things.Make(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, thing)
+	}
+	return out, nil
 }
 
-func (a *Animal) Color() string { return a.MyColor }
+func printThings(style zson.TypeStyle, names ...string) error {
+	ts, err := makeThings(names...)
+	if err != nil {
+		return err
+	}
+	for _, thing := range ts {
+		if err := output(out, style, thing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ex1(style zson.TypeStyle) error {
+	return printThings(style, "rose", "flamingo")
+}
 
-func Make(which string) Thing {
-	if which == "rose" {
-		return &Plant{"red"}
+func ex2(style zson.TypeStyle) error {
+	f, err := things.Make("flamingo")
+	if err != nil {
+		return err
 	}
-	if which == "ivy" {
-		return &Plant{"green"}
+	flamingoZSON, err := marshal(style, f)
+	if err != nil {
+		return err
 	}
-	if which == "flamingo" {
-		return &Animal{"pink"}
+
+	var flamingo things.Thing
+	if err := things.UnmarshalInto(newUnmarshaler(), flamingoZSON, &flamingo); err != nil {
+		return err
 	}
+	fmt.Fprintln(out, things.Describe(flamingo))
 	return nil
 }
 
-func ex1() {
-	rose := Make("rose")
-	flamingo := Make("flamingo")
-	m := zson.NewMarshaler()
-	m.Decorate(zson.StyleSimple)
-	roseZSON, _ := m.Marshal(rose)
-	fmt.Println(roseZSON)
-	flamingoZSON, _ := m.Marshal(flamingo)
-	fmt.Println(flamingoZSON)
+func ex3(style zson.TypeStyle) error {
+	f, err := things.Make("flamingo")
+	if err != nil {
+		return err
+	}
+	flamingoZSON, err := marshal(style, f)
+	if err != nil {
+		return err
+	}
+
+	var flamingo things.Thing
+	if err := things.UnmarshalInto(newUnmarshaler(), flamingoZSON, &flamingo); err != nil {
+		return err
+	}
+	_, ok := flamingo.(*things.Animal)
+	fmt.Fprintf(out, "%s is an Animal? %t\n", things.Describe(flamingo), ok)
+	return nil
 }
 
-func ex2() {
-	f := Make("flamingo")
-	m := zson.NewMarshaler()
-	m.Decorate(zson.StyleSimple)
-	flamingoZSON, _ := m.Marshal(f)
+var v0Bindings = []zson.Binding{
+	{Name: "Plant.v0", Template: things.Plant{}},
+	{Name: "Animal.v0", Template: things.Animal{}},
+}
 
-	u := zson.NewUnmarshaler()
-	u.Bind(Animal{}, Plant{})
-	var flamingo Thing
-	err := u.Unmarshal(flamingoZSON, &flamingo)
+// marshalV0 marshals the named Things using the versioned type names
+// in v0Bindings.
+func marshalV0(style zson.TypeStyle, names ...string) ([]string, error) {
+	ts, err := makeThings(names...)
 	if err != nil {
-		fmt.Println(err)
-	} else {
-		fmt.Println("The flamingo is " + flamingo.Color())
+		return nil, err
+	}
+	var out []string
+	for _, thing := range ts {
+		m := newMarshaler(style)
+		if err := m.NamedBindings(v0Bindings); err != nil {
+			return nil, err
+		}
+		s, err := m.Marshal(thing)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func ex5(style zson.TypeStyle) error {
+	if format == "json" {
+		// Named bindings only affect ZSON decorators.
+		return printThings(style, "rose", "flamingo")
+	}
+	values, err := marshalV0(style, "rose", "flamingo")
+	if err != nil {
+		return err
+	}
+	for _, s := range values {
+		if err := emit(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ex6(style zson.TypeStyle) error {
+	garden, err := makeThings("rose", "ivy", "flamingo")
+	if err != nil {
+		return err
 	}
+	return output(out, style, things.List(garden))
 }
 
-func ex3() {
-	f := Make("flamingo")
-	m := zson.NewMarshaler()
-	m.Decorate(zson.StyleSimple)
-	flamingoZSON, _ := m.Marshal(f)
+func ex7(style zson.TypeStyle) error {
+	garden, err := makeThings("rose", "ivy", "flamingo")
+	if err != nil {
+		return err
+	}
+	s, err := things.MarshalThings(newMarshaler(style), garden)
+	if err != nil {
+		return err
+	}
+
+	garden, err = things.UnmarshalThings(newUnmarshaler(), s)
+	if err != nil {
+		return err
+	}
+	for _, thing := range garden {
+		fmt.Fprintf(out, "The %s is %s\n", thing.Name(), thing.Color())
+	}
+	return nil
+}
+
+func ex8(style zson.TypeStyle) error {
+	contents, err := makeThings("rose", "flamingo")
+	if err != nil {
+		return err
+	}
+	s, err := marshal(style, &things.Garden{MyName: "backyard", Contents: contents})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+
+	var garden things.Garden
+	if err := things.UnmarshalInto(newUnmarshaler(), s, &garden); err != nil {
+		return err
+	}
+	for _, thing := range garden.Contents {
+		fmt.Fprintf(out, "The %s in the %s is %s\n", thing.Name(), garden.Name(), thing.Color())
+	}
+	return nil
+}
+
+func ex9(style zson.TypeStyle) error {
+	rose, err := things.Make("rose")
+	if err != nil {
+		return err
+	}
+	flamingo, err := things.Make("flamingo")
+	if err != nil {
+		return err
+	}
+	s, err := things.MarshalThingMap(newMarshaler(style), map[string]things.Thing{
+		"garden-rose":  rose,
+		"zoo-flamingo": flamingo,
+	})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+
+	m, err := things.UnmarshalThingMap(newUnmarshaler(), s)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s is %s\n", key, m[key].Color())
+	}
+	return nil
+}
 
+func ex10(style zson.TypeStyle) error {
+	garden, err := makeThings("rose", "ivy", "rose", "flamingo", "quartz")
+	if err != nil {
+		return err
+	}
+	s, err := things.MarshalThings(newMarshaler(style), garden)
+	if err != nil {
+		return err
+	}
+	garden, err = things.UnmarshalThings(newUnmarshaler(), s)
+	if err != nil {
+		return err
+	}
+	counts := things.CountByColor(garden)
+	colors := make([]string, 0, len(counts))
+	for color := range counts {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	for _, color := range colors {
+		fmt.Fprintf(out, "%s: %d\n", color, counts[color])
+	}
+	return nil
+}
+
+func ex11(style zson.TypeStyle) error {
+	values, err := marshalV0(style, "rose", "flamingo")
+	if err != nil {
+		return err
+	}
 	u := zson.NewUnmarshaler()
-	u.Bind(Animal{}, Plant{})
-	var flamingo Thing
-	u.Unmarshal(flamingoZSON, &flamingo)
-	_, ok := flamingo.(*Animal)
-	fmt.Printf("The flamingo is an Animal? %t\n", ok)
-}
-
-func ex4() {
-	rose := Make("rose")
-	flamingo := Make("flamingo")
-	m := zson.NewMarshaler()
-	m.Decorate(zson.StylePackage)
-	roseZSON, _ := m.Marshal(rose)
-	fmt.Println(roseZSON)
-	flamingoZSON, _ := m.Marshal(flamingo)
-	fmt.Println(flamingoZSON)
-}
-
-func ex5() {
-	rose := Make("rose")
-	flamingo := Make("flamingo")
-	m := zson.NewMarshaler()
-	m.NamedBindings([]zson.Binding{{"Plant.v0", Plant{}}, {"Animal.v0", Animal{}}})
-	roseZSON, _ := m.Marshal(rose)
-	fmt.Println(roseZSON)
-	flamingoZSON, _ := m.Marshal(flamingo)
-	fmt.Println(flamingoZSON)
+	if err := u.NamedBindings(v0Bindings); err != nil {
+		return err
+	}
+	for _, s := range values {
+		if err := emit(s); err != nil {
+			return err
+		}
+		var thing things.Thing
+		if err := things.UnmarshalInto(u, s, &thing); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "The %s is %s\n", thing.Name(), thing.Color())
+	}
+	return nil
 }
 
-func main() {
-	if len(os.Args) != 2 {
-		usage()
+// plantV0 is a Plant as persisted in version 0, before its color moved
+// into BaseThing and it gained a name.
+const plantV0 = `{MyColor:"red"}(=Plant.v0)`
+
+// A v0Plant has the fields of a Plant in version 0.
+type v0Plant struct {
+	MyColor string
+}
+
+// migratePlantV0 unmarshals the version 0 Plant s and moves its color
+// into the BaseThing of a current Plant.  zson would drop the MyColor
+// field of s if it were unmarshaled into a Plant directly, so a field
+// that a version 0 Plant did not have is an error rather than ignored.
+func migratePlantV0(s string) (*things.Plant, error) {
+	var old v0Plant
+	if err := things.UnmarshalInto(zson.NewUnmarshaler(), s, &old); err != nil {
+		return nil, err
 	}
-	ex, err := strconv.Atoi(os.Args[1])
+	if err := things.CheckFields(s, &old); err != nil {
+		return nil, fmt.Errorf("not a version 0 Plant: %w", err)
+	}
+	return &things.Plant{BaseThing: things.BaseThing{MyColor: old.MyColor}}, nil
+}
+
+func ex12(style zson.TypeStyle) error {
+	thing, err := migratePlantV0(plantV0)
 	if err != nil {
+		return err
+	}
+	if err := emit(plantV0); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%T name %q color %s\n", thing, thing.Name(), thing.Color())
+	return nil
+}
+
+func ex13(style zson.TypeStyle) error {
+	rose, err := things.Make("rose")
+	if err != nil {
+		return err
+	}
+	flamingo, err := things.Make("flamingo")
+	if err != nil {
+		return err
+	}
+	s, err := things.MarshalIntThingMap(newMarshaler(style), map[int]things.Thing{1: rose, 2: flamingo})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+
+	m, err := things.UnmarshalIntThingMap(newUnmarshaler(), s)
+	if err != nil {
+		return err
+	}
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%d is %s\n", key, m[key].Color())
+	}
+	return nil
+}
+
+func ex14(style zson.TypeStyle) error {
+	bindings := things.DecoratorBindings(things.Lowercase)
+	rose, err := things.Make("rose")
+	if err != nil {
+		return err
+	}
+	m := newMarshaler(style)
+	if err := m.NamedBindings(bindings); err != nil {
+		return err
+	}
+	s, err := m.Marshal(rose)
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+	u := newUnmarshaler()
+	if err := u.NamedBindings(bindings); err != nil {
+		return err
+	}
+	var thing things.Thing
+	if err := things.UnmarshalInto(u, s, &thing); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, things.Describe(thing))
+	return nil
+}
+
+// leaf returns the first Thing found by descending through the first
+// element of each nested Garden.
+func leaf(t things.Thing) things.Thing {
+	for {
+		g, ok := t.(*things.Garden)
+		if !ok || len(g.Contents) == 0 {
+			return t
+		}
+		t = g.Contents[0]
+	}
+}
+
+func ex15(style zson.TypeStyle) error {
+	rose, err := things.Make("rose")
+	if err != nil {
+		return err
+	}
+	ivy, err := things.Make("ivy")
+	if err != nil {
+		return err
+	}
+	bed := &things.Garden{MyName: "bed", Contents: []things.Thing{rose}}
+	plot := &things.Garden{MyName: "plot", Contents: []things.Thing{bed, ivy}}
+	park := &things.Garden{MyName: "park", Contents: []things.Thing{plot}}
+	s, err := marshal(style, park)
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+	var thing things.Thing
+	if err := things.UnmarshalInto(newUnmarshaler(), s, &thing); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, things.Describe(thing))
+	fmt.Fprintf(out, "innermost: %s\n", things.Describe(leaf(thing)))
+	return nil
+}
+
+// holder has a single Thing field to show how a nil interface value is
+// marshaled.
+type holder struct {
+	Thing things.Thing
+}
+
+func ex16(style zson.TypeStyle) error {
+	s, err := marshal(style, &things.Garden{MyName: "empty"})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+	var garden things.Garden
+	if err := things.UnmarshalInto(newUnmarshaler(), s, &garden); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Contents is nil: %t\n", garden.Contents == nil)
+
+	s, err = marshal(style, holder{})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+	h := holder{Thing: things.MustMake("rose")}
+	if err := things.UnmarshalInto(newUnmarshaler(), s, &h); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Thing is nil: %t\n", h.Thing == nil)
+	return nil
+}
+
+func ex17(style zson.TypeStyle) error {
+	north, err := makeThings("rose", "flamingo")
+	if err != nil {
+		return err
+	}
+	south, err := makeThings("ivy", "quartz", "emerald")
+	if err != nil {
+		return err
+	}
+	s, err := things.MarshalThingSliceMap(newMarshaler(style), map[string][]things.Thing{
+		"north": north,
+		"south": south,
+	})
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+
+	regions, err := things.UnmarshalThingSliceMap(newUnmarshaler(), s)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(regions))
+	for key := range regions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, thing := range regions[key] {
+			fmt.Fprintf(out, "%s: %s\n", key, things.Describe(thing))
+		}
+	}
+	return nil
+}
+
+func ex18(style zson.TypeStyle) error {
+	lamp := things.NewLamp("yellow", "lamp")
+	s, err := things.MarshalThing(newMarshaler(style), lamp)
+	if err != nil {
+		return err
+	}
+	if err := emit(s); err != nil {
+		return err
+	}
+	var restored things.Lamp
+	if err := things.UnmarshalState(newUnmarshaler(), s, &restored); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, things.Describe(&restored))
+	return nil
+}
+
+type example struct {
+	name  string
+	num   int
+	desc  string
+	style zson.TypeStyle
+	run   func(zson.TypeStyle) error
+}
+
+var examples = []example{
+	{"simple", 1, "marshal a Plant and an Animal with StyleSimple decorators", zson.StyleSimple, ex1},
+	{"unmarshal", 2, "unmarshal a decorated Animal into a Thing interface value", zson.StyleSimple, ex2},
+	{"type-assert", 3, "check the concrete type of an unmarshaled Thing", zson.StyleSimple, ex3},
+	{"package-style", 4, "marshal with StylePackage decorators", zson.StylePackage, ex1},
+	{"named-bindings", 5, "marshal with versioned NamedBindings type names", zson.StyleNone, ex5},
+	{"slice", 6, "marshal a slice of mixed Things as one ZSON list value", zson.StyleSimple, ex6},
+	{"slice-roundtrip", 7, "unmarshal a ZSON list back into a slice of Things", zson.StyleSimple, ex7},
+	{"garden", 8, "round-trip a Garden struct whose Contents are decorated Things", zson.StyleSimple, ex8},
+	{"map", 9, "round-trip a map of Things with keys in sorted order", zson.StyleSimple, ex9},
+	{"colors", 10, "count unmarshaled Things by color", zson.StyleSimple, ex10},
+	{"named-unmarshal", 11, "unmarshal NamedBindings output using the same versioned names", zson.StyleNone, ex11},
+	{"schema-evolution", 12, "unmarshal an old Plant.v0 value into the current Plant struct", zson.StyleNone, ex12},
+	{"int-map", 13, "round-trip a map of Things with integer keys", zson.StyleSimple, ex13},
+	{"custom-decorator", 14, "decorate with lowercased type names from a custom Decorator", zson.StyleSimple, ex14},
+	{"nested-garden", 15, "round-trip Gardens nested three levels deep", zson.StyleSimple, ex15},
+	{"nil-fields", 16, "marshal a nil Contents slice and a nil Thing field as null", zson.StyleSimple, ex16},
+	{"regions", 17, "round-trip a map of Thing slices with keys in sorted order", zson.StyleSimple, ex17},
+	{"private-state", 18, "marshal a Thing's unexported state through MarshalState", zson.StyleSimple, ex18},
+}
+
+func lookupExample(arg string) (example, bool) {
+	for _, ex := range examples {
+		if arg == ex.name || arg == strconv.Itoa(ex.num) {
+			return ex, true
+		}
+	}
+	return example{}, false
+}
+
+func list(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ex := range examples {
+		fmt.Fprintf(tw, "ex%d\t%s\t%s\n", ex.num, ex.name, ex.desc)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nthings:")
+	for _, name := range things.Names() {
+		thing, err := things.Make(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", name, paint(w, things.Describe(thing), thing.Color()))
+	}
+	return tw.Flush()
+}
+
+func all(style *zson.TypeStyle) error {
+	var failed int
+	for _, ex := range examples {
+		fmt.Fprintf(out, "=== ex%d ===\n", ex.num)
+		if err := ex.exec(style); err != nil {
+			fmt.Fprintf(os.Stderr, "ex%d: %s\n", ex.num, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples failed", failed, len(examples))
+	}
+	return nil
+}
+
+func (e example) exec(style *zson.TypeStyle) error {
+	return e.run(styleOr(style, e.style))
+}
+
+func styleOr(style *zson.TypeStyle, def zson.TypeStyle) zson.TypeStyle {
+	if style != nil {
+		return *style
+	}
+	return def
+}
+
+func main() {
+	styleFlag := flag.String("style", "", "decoration style overriding each example's default ("+styleNames()+")")
+	flag.StringVar(&format, "format", "zson", "output format of marshaled values (zson, json)")
+	decorator := flag.String("decorator", "", "with -style=simple, decorate type names with the custom decorator `name` (lower)")
+	prefix := flag.String("prefix", "", "with -style=simple, decorate type names as `prefix`.TypeName")
+	flag.BoolVar(&noColor, "no-color", false, "disable colored output on terminals")
+	flag.BoolVar(&verbose, "v", false, "log bindings and decoded types to stderr, even with -quiet")
+	flag.BoolVar(&quiet, "quiet", false, "print nothing but errors, except to the -o file")
+	flag.BoolVar(&pretty, "pretty", false, "format output with indentation")
+	flag.BoolVar(&prettySortKeys, "pretty-sort-keys", false, "with -pretty, order record fields by name")
+	flag.IntVar(&prettyIndent, "indent", 4, "indentation width from 0 to 8 used by -pretty")
+	flag.StringVar(&outPath, "o", "", "write output to `file` instead of stdout")
+	flag.BoolVar(&dryRun, "dry-run", false, "do the work but discard the output, reporting its size on stderr")
+	flag.BoolVar(&appendOut, "append", false, "with -o, append to the file instead of truncating it")
+	flag.CommandLine.Init("zmarshal", flag.ContinueOnError)
+	flag.CommandLine.SetOutput(os.Stderr)
+	flag.Usage = func() {}
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			help()
+		}
 		usage()
 	}
-	switch ex {
-	case 1:
-		ex1()
-	case 2:
-		ex2()
-	case 3:
-		ex3()
-	case 4:
-		ex4()
-	case 5:
-		ex5()
+	cmd, args := flag.Arg(0), flag.Args()
+	if len(args) > 0 {
+		args = args[1:]
+	} else {
+		// In containers, where arguments are awkward, the example
+		// may come from the environment instead.
+		cmd = os.Getenv("ZMARSHAL_EXAMPLE")
+		if cmd == "" {
+			usage()
+		}
+		if _, ok := lookupExample(cmd); !ok {
+			fatal(fmt.Errorf("ZMARSHAL_EXAMPLE: unknown example %q", cmd))
+		}
+	}
+	var style *zson.TypeStyle
+	if *styleFlag != "" {
+		s, err := parseStyle(*styleFlag)
+		if err != nil {
+			fatal(err)
+		}
+		style = &s
+	}
+	if prettyIndent < 0 || prettyIndent > 8 {
+		fatal(fmt.Errorf("-indent must be between 0 and 8: %d", prettyIndent))
+	}
+	if appendOut && outPath == "" {
+		fatal(errors.New("-append requires -o"))
+	}
+	if prettySortKeys && (!pretty || prettyIndent == 0) {
+		fatal(errors.New("-pretty-sort-keys requires -pretty with a nonzero -indent"))
+	}
+	if *prefix != "" {
+		if style == nil || *style != zson.StyleSimple {
+			fatal(errors.New("-prefix requires -style=simple"))
+		}
+		nameBindings = things.PrefixBindings(*prefix)
+	}
+	if *decorator != "" {
+		if style == nil || *style != zson.StyleSimple {
+			fatal(errors.New("-decorator requires -style=simple"))
+		}
+		if *prefix != "" {
+			fatal(errors.New("-decorator cannot be combined with -prefix"))
+		}
+		d, ok := decorators[*decorator]
+		if !ok {
+			fatal(fmt.Errorf("unknown decorator %q (valid decorators: lower)", *decorator))
+		}
+		nameBindings = things.DecoratorBindings(d)
+	}
+	switch format {
+	case "zson":
+	case "json":
+		if style != nil && *style != zson.StyleNone {
+			fatal(fmt.Errorf("-format=json cannot be combined with -style=%s: JSON cannot carry decorators", *styleFlag))
+		}
 	default:
+		fatal(fmt.Errorf("unknown format %q (valid formats: zson, json)", format))
+	}
+	var f *outFile
+	var dry *countingWriter
+	if dryRun {
+		dry = &countingWriter{w: ioutil.Discard}
+		out = dry
+	} else if outPath != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if appendOut {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		f = &outFile{path: outPath, flags: flags}
+		out = f
+	}
+	if quiet {
+		info = ioutil.Discard
+		if f == nil {
+			out = ioutil.Discard
+		}
+	}
+	if prettySortKeys {
+		out = &sortingWriter{w: out}
+	}
+	err := run(style, cmd, args)
+	// Leave the -o file untouched if the command failed without
+	// writing to it.
+	if f != nil && (err == nil || f.f != nil) {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	// convert-dir writes files of its own rather than out and reports
+	// what it would have written to them.
+	if dry != nil && cmd != "convert-dir" {
+		target := outPath
+		if target == "" {
+			target = "stdout"
+		}
+		fmt.Fprintf(info, "dry run: would have written %d bytes in %d lines to %s\n", dry.n, dry.nlines, target)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// run runs the command or example cmd with args, writing its output to
+// out.
+func run(style *zson.TypeStyle, cmd string, args []string) error {
+	switch cmd {
+	case "help":
+		help()
+	case "list":
+		return list(out)
+	case "all":
+		return all(style)
+	case "bulk":
+		return bulk(out, styleOr(style, zson.StyleSimple), args)
+	case "canonicalize":
+		if len(args) != 0 {
+			usage()
+		}
+		return canonicalize(out, os.Stdin, styleOr(style, zson.StyleSimple))
+	case "compare":
+		return compare(out, args)
+	case "convert-dir":
+		return convertDir(out, styleOr(style, zson.StyleSimple), args)
+	case "decode":
+		return decode(out, os.Stdin, args)
+	case "diff":
+		if len(args) != 2 {
+			usage()
+		}
+		return diffThings(out, styleOr(style, zson.StyleSimple), args[0], args[1])
+	case "encode":
+		if len(args) != 0 {
+			usage()
+		}
+		return encode(out, os.Stdin, styleOr(style, zson.StyleSimple))
+	case "fields":
+		if len(args) != 1 {
+			usage()
+		}
+		return fields(out, args[0])
+	case "import":
+		if len(args) != 0 {
+			usage()
+		}
+		return importJSON(out, os.Stdin, styleOr(style, zson.StyleSimple))
+	case "roundtrip":
+		return roundtrip(out, os.Stdin, styleOr(style, zson.StyleSimple), args)
+	case "sample":
+		return sample(out, styleOr(style, zson.StyleSimple), args)
+	case "stats":
+		return stats(out, args)
+	case "togo":
+		if len(args) != 1 {
+			usage()
+		}
+		return togo(out, styleOr(style, zson.StyleSimple), args[0])
+	case "typeof":
+		if len(args) != 1 {
+			usage()
+		}
+		return typeOf(out, styleOr(style, zson.StyleSimple), args[0])
+	case "version":
+		if len(args) != 0 {
+			usage()
+		}
+		version(out)
+		return nil
+	case "watch":
+		if len(args) != 1 {
+			usage()
+		}
+		return watch(out, styleOr(style, zson.StyleSimple), args[0])
+	case "verify":
+		return verify(out, styleOr(style, zson.StyleSimple), args)
+	}
+	ex, ok := lookupExample(cmd)
+	if !ok || len(args) != 0 {
 		usage()
 	}
+	return ex.exec(style)
+}
+
+// exitTimeout is the exit status when a command stops at its -timeout
+// deadline, so that scripts can tell a timeout from other failures.
+const exitTimeout = 3
+
+// exitInterrupt is the conventional exit status after SIGINT, with
+// which a command exits when an interrupt stops it cleanly.
+const exitInterrupt = 130
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		os.Exit(exitTimeout)
+	case errors.Is(err, context.Canceled):
+		os.Exit(exitInterrupt)
+	}
+	os.Exit(1)
+}
+
+var commands = []struct {
+	usage string
+	desc  string
+}{
+	{"help", "print this help"},
+	{"list", "list the examples and the registered Things"},
+	{"all", "run every example in order"},
+	{"bulk [-count N] [-seed S] [-timeout D] [-wrap-list]", "marshal N random Things and report throughput on stderr"},
+	{"canonicalize", "re-marshal a stream of Things from stdin in compact form"},
+	{"compare [name ...]", "print the named Things, or all of them, under every style"},
+	{"convert-dir in out [-style S]", "re-marshal each .zson file in directory in to the same name in out"},
+	{"decode [-strict] [-type T] [-max-bytes N] [-null-as-empty] [-columns] [-select F] [-flatten] [-limit N] [file]", "describe the decorated Things read from file or stdin"},
+	{"diff name1 name2", "print a line diff of the ZSON of two Things"},
+	{"encode", "marshal the Thing named by a {which:\"name\"} record on stdin"},
+	{"fields name", "list the exported fields, Go types, and tags of a Thing's type"},
+	{"import", "marshal the Things named by a JSON array of {\"which\":name} on stdin"},
+	{"roundtrip [-fail-fast=false] [-type T] [-max-bytes N] [-timeout D] [-only-changed] [-limit N]", "re-marshal a stream of Things from stdin with the chosen style"},
+	{"sample name [-style S]", "print a Thing's ZSON as a template for input, with its fields on stderr"},
+	{"stats [name ...]", "print the serialized size of the named Things, or all of them, under every style"},
+	{"togo name", "print a Go literal of a Thing as decoded from its ZSON"},
+	{"typeof name", "print the Zed type of the named Thing under the chosen style"},
+	{"verify name [-assert-type T]", "check that marshal, unmarshal, and re-marshal of a Thing is stable"},
+	{"version", "print the zmarshal and zed module versions"},
+	{"watch file", "pretty-print the Things in file again each time it changes"},
+	{"example", "run the example with the given name or number"},
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: zmarshal [flags] command [args]")
+	fmt.Fprintln(w, "With no command, the example named by $ZMARSHAL_EXAMPLE is run.")
+	fmt.Fprintln(w, "\ncommands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.usage, c.desc)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nexamples:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ex := range examples {
+		fmt.Fprintf(tw, "  %d\t%s\t%s\n", ex.num, ex.name, ex.desc)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nflags:")
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+}
+
+// help prints the full usage to stdout and exits successfully.
+func help() {
+	printUsage(os.Stdout)
+	os.Exit(0)
 }
 
+// usage prints the full usage to stderr and exits with an error.
 func usage() {
-	fmt.Fprintln(os.Stderr, "usage: zmarshal example-number")
+	printUsage(os.Stderr)
 	os.Exit(1)
 }
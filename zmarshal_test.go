@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brimdata/zed/zson"
+	"github.com/mccanne/zmarshal/things"
+)
+
+// capture returns what fn writes to out.
+func capture(t *testing.T, fn func() error) string {
+	t.Helper()
+	saved := out
+	defer func() { out = saved }()
+	var b bytes.Buffer
+	out = &b
+	if err := fn(); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestEmit(t *testing.T) {
+	got := capture(t, func() error {
+		if err := emit(`{a:1}`); err != nil {
+			return err
+		}
+		return emit("{b:2}\n")
+	})
+	if want := "{a:1}\n{b:2}\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	rose, err := marshal(zson.StyleSimple, things.MustMake("rose"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flamingo, err := marshal(zson.StyleSimple, things.MustMake("flamingo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = capture(t, func() error { return run(nil, "simple", nil) })
+	if want := rose + "\n" + flamingo + "\n"; got != want {
+		t.Errorf("ex1: got %q, want %q", got, want)
+	}
+}
+
+func TestMarshalIsStandalone(t *testing.T) {
+	rose := things.MustMake("rose")
+	for _, s := range things.Styles() {
+		first, err := marshal(s.Style, rose)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		second, err := marshal(s.Style, rose)
+		if err != nil {
+			t.Fatalf("%s: %s", s.Name, err)
+		}
+		if first != second {
+			t.Errorf("%s: second marshal of the same Thing differs:\n%s\n%s", s.Name, first, second)
+		}
+	}
+}
+
+func TestVersionedNames(t *testing.T) {
+	values, err := marshalV0(zson.StyleSimple, "rose", "flamingo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := zson.NewUnmarshaler()
+	if err := u.NamedBindings(v0Bindings); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range []string{"Plant.v0", "Animal.v0"} {
+		if !strings.HasSuffix(values[k], "(="+want+")") {
+			t.Errorf("value %d is not decorated %s: %s", k, want, values[k])
+		}
+		var thing things.Thing
+		if err := things.UnmarshalInto(u, values[k], &thing); err != nil {
+			t.Errorf("value %d: %s", k, err)
+		}
+	}
+
+	wrong := zson.NewUnmarshaler()
+	if err := wrong.NamedBindings([]zson.Binding{{Name: "Plant.v1", Template: things.Plant{}}}); err != nil {
+		t.Fatal(err)
+	}
+	var thing things.Thing
+	err = things.UnmarshalInto(wrong, values[0], &thing)
+	if !errors.Is(err, things.ErrUnboundType) || !strings.Contains(err.Error(), `"Plant.v0"`) {
+		t.Errorf("unmarshal with the wrong version: got %v, want an unbound type error naming Plant.v0", err)
+	}
+}
+
+func TestEx6(t *testing.T) {
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		text := capture(t, func() error { return ex6(s.Style) })
+		garden, err := things.UnmarshalThings(newUnmarshaler(), strings.TrimSpace(text))
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", s.Name, err, text)
+		}
+		var got []string
+		for _, thing := range garden {
+			got = append(got, things.TypeName(thing, zson.StyleSimple)+" "+thing.Name())
+		}
+		if want := "Plant rose,Plant ivy,Animal flamingo"; strings.Join(got, ",") != want {
+			t.Errorf("%s: ex6 output unmarshals to %s, want %s", s.Name, strings.Join(got, ","), want)
+		}
+	}
+}
+
+func TestEx10(t *testing.T) {
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		got := capture(t, func() error { return ex10(s.Style) })
+		if want := "green: 1\npink: 1\nred: 2\nwhite: 1\n"; got != want {
+			t.Errorf("%s: got\n%swant\n%s", s.Name, got, want)
+		}
+	}
+}
+
+func TestPlantV0(t *testing.T) {
+	tests := []struct {
+		zson  string
+		color string
+		err   string
+	}{
+		{plantV0, "red", ""},
+		{`{MyColor:"green"}(=Plant.v0)`, "green", ""},
+		{`{MyColor:""}(=Plant.v0)`, "", ""},
+		{`{BaseThing:{color:"green"}}(=Plant.v0)`, "", `unexpected field "BaseThing"`},
+		{`{MyColor:"green",MyName:"ivy"}(=Plant.v0)`, "", `unexpected field "MyName"`},
+	}
+	for _, tc := range tests {
+		p, err := migratePlantV0(tc.zson)
+		if tc.err != "" {
+			if !errors.Is(err, things.ErrBadInput) || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: got %v, want a bad input error containing %q", tc.zson, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tc.zson, err)
+		}
+		if p.MyName != "" || p.Color() != tc.color {
+			t.Errorf("%s: got name %q and color %q, want an empty name and %q", tc.zson, p.MyName, p.Color(), tc.color)
+		}
+	}
+}
+
+func TestEx15(t *testing.T) {
+	for _, s := range things.Styles() {
+		if s.Style == zson.StyleNone {
+			continue
+		}
+		got := capture(t, func() error { return ex15(s.Style) })
+		if want := "innermost: Plant(red) named \"rose\"\n"; !strings.HasSuffix(got, want) {
+			t.Errorf("%s: output does not end with %q:\n%s", s.Name, want, got)
+		}
+	}
+}
+
+func TestEx16(t *testing.T) {
+	got := capture(t, func() error { return ex16(zson.StyleSimple) })
+	want := `{Name:"empty",Contents:null(List=[null])}(=Garden)
+Contents is nil: true
+{Thing:null}(=holder)
+Thing is nil: true
+`
+	if got != want {
+		t.Errorf("got\n%swant\n%s", got, want)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	defer func() { nameBindings = nil }()
+	for _, prefix := range []string{"myorg", "example.com/things"} {
+		nameBindings = things.PrefixBindings(prefix)
+		for _, name := range things.Names() {
+			thing := things.MustMake(name)
+			s, err := marshal(zson.StyleSimple, thing)
+			if err != nil {
+				t.Fatalf("%s, %s: %s", prefix, name, err)
+			}
+			typ := things.TypeName(thing, zson.StyleSimple)
+			if !strings.HasSuffix(s, "(="+prefix+"."+typ+")") {
+				t.Errorf("%s, %s: %s is not decorated %s.%s", prefix, name, s, prefix, typ)
+			}
+			var got things.Thing
+			if err := things.UnmarshalInto(newUnmarshaler(), s, &got); err != nil {
+				t.Errorf("%s, %s: %s", prefix, name, err)
+			} else if things.Describe(got) != things.Describe(thing) {
+				t.Errorf("%s, %s: unmarshaled %s, want %s", prefix, name, things.Describe(got), things.Describe(thing))
+			}
+			u := zson.NewUnmarshaler()
+			if err := things.BindAll(u); err != nil {
+				t.Fatal(err)
+			}
+			if err := things.UnmarshalInto(u, s, &got); !errors.Is(err, things.ErrUnboundType) {
+				t.Errorf("%s, %s: unmarshal without the prefix bindings: got %v, want an unbound type error", prefix, name, err)
+			}
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, s := range things.Styles() {
+		style, err := parseStyle(s.Name)
+		if err != nil || style != s.Style {
+			t.Errorf("%s: got %d and %v, want %d", s.Name, style, err, s.Style)
+		}
+	}
+	for _, name := range []string{"", "Simple", "full", "pkg"} {
+		_, err := parseStyle(name)
+		if want := "(valid styles: none, simple, package)"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got %v, want an error listing the valid styles", name, err)
+		}
+	}
+}